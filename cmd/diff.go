@@ -0,0 +1,68 @@
+/*
+Copyright 2019 Philippe Martin.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/feloy/kubectl-reference/generators"
+	"github.com/feloy/kubectl-reference/lang"
+)
+
+func NewDiffCommand() *cobra.Command {
+	opts := &generators.GenerateOptions{}
+	var from, to, output, format string
+	c := &cobra.Command{
+		Use:   "diff",
+		Short: "Generate the changelog between two versions, from their toc.yaml or spec files",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(from) == 0 || len(to) == 0 {
+				return fmt.Errorf("must specify --from and --to")
+			}
+			if len(opts.Lang) > 0 {
+				if err := lang.Check(opts.Lang, false); err != nil {
+					return err
+				}
+			}
+			changelog, err := generators.Diff(from, to)
+			if err != nil {
+				return err
+			}
+			return withOutput(output, func(w io.Writer) error {
+				switch format {
+				case "markdown":
+					changelog.AsMarkdown(w, opts)
+				case "docbook":
+					changelog.AsDocbook(w, opts)
+				default:
+					return fmt.Errorf("unknown format %q", format)
+				}
+				return nil
+			})
+		},
+	}
+	c.Flags().StringVar(&from, "from", "", "toc.yaml or spec file of the previous version (e.g. generators/v1_18/toc.yaml)")
+	c.Flags().StringVar(&to, "to", "", "toc.yaml or spec file of the new version (e.g. generators/v1_19/toc.yaml)")
+	c.Flags().StringVarP(&output, "output", "o", "-", "File to write the result to, or - for stdout")
+	c.Flags().StringVarP(&format, "format", "f", "markdown", "Output format, one of: markdown, docbook")
+	c.Flags().StringVar(&opts.Lang, "lang", "", fmt.Sprintf("Language of the changelog, one of %v, English by default", lang.Names()))
+	return c
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2019 Philippe Martin.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/feloy/kubectl-reference/generators"
+)
+
+func NewExportCommand() *cobra.Command {
+	opts := &generators.GenerateOptions{}
+	var output, format string
+	c := &cobra.Command{
+		Use:   "export",
+		Short: "Export the kubectl commands merged with the ToC overrides, as JSON or YAML",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withOutput(output, func(w io.Writer) error {
+				return generators.Export(w, format, opts)
+			})
+		},
+	}
+	addVersionFlags(c.Flags(), opts)
+	addSpecFlags(c.Flags(), opts)
+	c.Flags().BoolVar(&opts.ShowHidden, "show-hidden", false, "List the hidden commands and options")
+	c.Flags().StringVarP(&output, "output", "o", "-", "File to write the result to, or - for stdout")
+	c.Flags().StringVarP(&format, "format", "f", "json", "Output format, one of: json, yaml")
+	return c
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2019 Philippe Martin.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/feloy/kubectl-reference/generators"
+)
+
+func NewGenerateCommand() *cobra.Command {
+	opts := &generators.GenerateOptions{}
+	var output, format string
+	c := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate the reference of a Kubernetes version",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch format {
+			case "docbook":
+				return withOutput(output, func(w io.Writer) error {
+					return generators.Generate(w, opts)
+				})
+			case "markdown":
+				if output == "-" {
+					return fmt.Errorf("the %s format needs an output directory", format)
+				}
+				return generators.GenerateMarkdown(output, opts)
+			case "html":
+				return withOutput(output, func(w io.Writer) error {
+					return generators.GenerateHTML(w, opts)
+				})
+			case "html-pages":
+				if output == "-" {
+					return fmt.Errorf("the %s format needs an output directory", format)
+				}
+				return generators.GenerateHTMLPages(output, opts)
+			case "man":
+				if output == "-" {
+					return fmt.Errorf("the %s format needs an output directory", format)
+				}
+				return generators.GenerateMan(output, opts)
+			default:
+				return fmt.Errorf("unknown format %q", format)
+			}
+		},
+	}
+	addVersionFlags(c.Flags(), opts)
+	addSpecFlags(c.Flags(), opts)
+	c.Flags().BoolVar(&opts.ShowUsage, "show-usage", false, "Show original usage (for debugging)")
+	c.Flags().StringVar(&opts.ChangelogFrom, "changelog-from", "", "toc.yaml or spec file of a previous version, to add the changes since this version as an appendix (docbook format only)")
+	c.Flags().BoolVar(&opts.ShowHidden, "show-hidden", false, "Document the hidden commands and options")
+	c.Flags().BoolVar(&opts.SkipDeprecated, "skip-deprecated", false, "Omit the deprecated commands")
+	c.Flags().BoolVar(&opts.OptionsMatrix, "options-matrix", false, "Add an appendix listing the commands accepting each option (docbook format only)")
+	c.Flags().StringSliceVar(&opts.MatrixOptions, "matrix-options", nil, "Options to list in the options matrix, all by default")
+	c.Flags().StringVarP(&output, "output", "o", "-", "File to write the result to, or - for stdout. Directory for the markdown, html-pages and man formats")
+	c.Flags().StringVarP(&format, "format", "f", "docbook", "Output format, one of: docbook, markdown, html, html-pages, man")
+	return c
+}
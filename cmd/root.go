@@ -0,0 +1,119 @@
+/*
+Copyright 2019 Philippe Martin.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/feloy/kubectl-reference/generators"
+	"github.com/feloy/kubectl-reference/lang"
+)
+
+// NewRootCommand returns the kubectl-reference command, with all its subcommands
+func NewRootCommand() *cobra.Command {
+	root := &cobra.Command{
+		Use:          "kubectl-reference",
+		Short:        "Create a DocBook documentation from the Kubectl inline help",
+		SilenceUsage: true,
+	}
+	root.AddCommand(
+		NewGenerateCommand(),
+		NewUpgradeCommand(),
+		NewExportCommand(),
+		NewValidateCommand(),
+		NewDiffCommand(),
+		NewSnapshotCommand(),
+		NewMatrixCommand(),
+	)
+	return root
+}
+
+// addVersionFlags adds the flags locating the files of a Kubernetes version
+func addVersionFlags(flags *pflag.FlagSet, opts *generators.GenerateOptions) {
+	flags.StringVar(&opts.KubernetesVersion, "kubernetes-version", "", "Version of Kubernetes to generate docs for (e.g. v1_31).")
+	flags.StringVar(&opts.GenKubectlDir, "gen-kubectl-dir", "generators", "Directory containing kubectl files")
+	flags.StringVar(&opts.Lang, "lang", "", fmt.Sprintf("Language of the reference, one of %v, English by default. The kubectl help texts are translated when the tool starts, so the flag must be given on the command line", lang.Names()))
+	flags.StringVar(&opts.Product, "product", generators.DefaultProduct, fmt.Sprintf("Binary of the product to document, one of %v, or any binary with --help-binary or --help-dir", generators.GetProductBinaries()))
+}
+
+// addSpecFlags adds the flags selecting the source of the spec
+func addSpecFlags(flags *pflag.FlagSet, opts *generators.GenerateOptions) {
+	flags.BoolVar(&opts.LiveSpec, "live-spec", false, "Extract the spec from the kubectl compiled in, even if a spec.yaml snapshot exists")
+	addHelpFlags(flags, opts)
+}
+
+// addHelpFlags adds the flags getting the spec from the help texts of a CLI which is not compiled in
+func addHelpFlags(flags *pflag.FlagSet, opts *generators.GenerateOptions) {
+	flags.StringVar(&opts.HelpBinary, "help-binary", "", "Executable whose --help output is parsed to get the spec (e.g. a kubectl plugin), instead of the product compiled in")
+	flags.StringVar(&opts.HelpDir, "help-dir", "", "Directory containing the captured help texts of the commands (e.g. kubectl_foo.txt, kubectl_foo_bar.txt), parsed to get the spec")
+}
+
+// withOutput calls fn with a writer to the given file, or to stdout if filename is "-".
+// The result is written to a temporary file, renamed to filename only if fn succeeds
+// or only skipped some commands, so an existing file is not replaced by a broken result
+func withOutput(filename string, fn func(w io.Writer) error) error {
+	if filename == "-" {
+		return fn(os.Stdout)
+	}
+	f, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".*")
+	if err != nil {
+		return err
+	}
+	fnErr := fn(f)
+	if fnErr != nil && !onlySkipped(fnErr) {
+		f.Close()
+		os.Remove(f.Name())
+		return fnErr
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	// os.CreateTemp creates the file readable by its owner only
+	if err := os.Chmod(f.Name(), 0644); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if err := os.Rename(f.Name(), filename); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return fnErr
+}
+
+// onlySkipped returns true if err only reports commands or options of the ToC not found,
+// which are skipped from the result
+func onlySkipped(err error) bool {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			if !onlySkipped(e) {
+				return false
+			}
+		}
+		return true
+	}
+	var commandErr *generators.CommandNotFoundError
+	var optionErr *generators.OptionNotFoundError
+	return errors.As(err, &commandErr) || errors.As(err, &optionErr)
+}
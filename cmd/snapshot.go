@@ -0,0 +1,62 @@
+/*
+Copyright 2019 Philippe Martin.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/feloy/kubectl-reference/generators"
+)
+
+func NewSnapshotCommand() *cobra.Command {
+	opts := &generators.GenerateOptions{}
+	var output string
+	c := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Save the spec extracted from the kubectl compiled in, to generate the reference later from this snapshot",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(output) == 0 {
+				if len(opts.KubernetesVersion) == 0 {
+					return fmt.Errorf("must specify --kubernetes-version or --output")
+				}
+				output = opts.GetSpecFile()
+			}
+			product, err := opts.GetProduct()
+			if err != nil {
+				return err
+			}
+			if err := opts.CheckLang(product); err != nil {
+				return err
+			}
+			spec, err := product.GetSpec()
+			if err != nil {
+				return err
+			}
+			return withOutput(output, func(w io.Writer) error {
+				return generators.WriteSpec(w, &spec)
+			})
+		},
+	}
+	addVersionFlags(c.Flags(), opts)
+	addHelpFlags(c.Flags(), opts)
+	c.Flags().StringVarP(&output, "output", "o", "", "File to write the spec to, or - for stdout. Defaults to the spec.yaml file of the version")
+	return c
+}
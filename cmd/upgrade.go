@@ -0,0 +1,49 @@
+/*
+Copyright 2019 Philippe Martin.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/feloy/kubectl-reference/generators"
+	"github.com/feloy/kubectl-reference/upgrade"
+)
+
+func NewUpgradeCommand() *cobra.Command {
+	opts := &generators.GenerateOptions{}
+	var output string
+	c := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Print the toc.yaml of a Kubernetes version completed with the missing commands, options and usages",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(opts.KubernetesVersion) < 1 {
+				return fmt.Errorf("must specify --kubernetes-version")
+			}
+			return withOutput(output, func(w io.Writer) error {
+				return upgrade.Upgrade(w, opts)
+			})
+		},
+	}
+	addVersionFlags(c.Flags(), opts)
+	addSpecFlags(c.Flags(), opts)
+	c.Flags().StringVarP(&output, "output", "o", "-", "File to write the result to, or - for stdout")
+	return c
+}
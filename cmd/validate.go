@@ -0,0 +1,86 @@
+/*
+Copyright 2019 Philippe Martin.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/feloy/kubectl-reference/generators"
+)
+
+func NewValidateCommand() *cobra.Command {
+	opts := &generators.GenerateOptions{}
+	var output, format string
+	c := &cobra.Command{
+		Use:   "validate",
+		Short: "Check the toc.yaml of a Kubernetes version against the kubectl command tree",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "text" && format != "json" {
+				return fmt.Errorf("unknown format %q", format)
+			}
+			problems, err := generators.ValidateVersion(opts)
+			if err != nil {
+				return err
+			}
+			err = withOutput(output, func(w io.Writer) error {
+				return writeProblems(w, format, problems)
+			})
+			if err != nil {
+				return err
+			}
+			errors := 0
+			for _, problem := range problems {
+				if !problem.Warning {
+					errors++
+				}
+			}
+			if errors > 0 {
+				return fmt.Errorf("%d problems found", errors)
+			}
+			return nil
+		},
+	}
+	addVersionFlags(c.Flags(), opts)
+	addSpecFlags(c.Flags(), opts)
+	c.Flags().StringVarP(&output, "output", "o", "-", "File to write the report to, or - for stdout")
+	c.Flags().StringVarP(&format, "format", "f", "text", "Format of the report, one of: text, json")
+	return c
+}
+
+func writeProblems(w io.Writer, format string, problems []generators.Problem) error {
+	if format == "json" {
+		if problems == nil {
+			problems = []generators.Problem{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(problems)
+	}
+	for _, problem := range problems {
+		if problem.Warning {
+			fmt.Fprintf(w, "warning: %s: %s\n", problem.Kind, problem.Message)
+			continue
+		}
+		fmt.Fprintf(w, "%s: %s\n", problem.Kind, problem.Message)
+	}
+	return nil
+}
@@ -0,0 +1,348 @@
+/*
+Copyright 2019 Philippe Martin.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ChangeKind is the kind of a change between two versions
+type ChangeKind string
+
+const (
+	AddedCommand        ChangeKind = "added-command"
+	RemovedCommand      ChangeKind = "removed-command"
+	ChangedCommandUsage ChangeKind = "changed-command-usage"
+	AddedOption         ChangeKind = "added-option"
+	RemovedOption       ChangeKind = "removed-option"
+	ChangedDefault      ChangeKind = "changed-default"
+	ChangedType         ChangeKind = "changed-type"
+	ChangedUsage        ChangeKind = "changed-usage"
+	DeprecatedOption    ChangeKind = "deprecated-option"
+)
+
+// changeKinds lists the kinds of changes, in the order they are rendered, with their titles
+var changeKinds = []struct {
+	kind  ChangeKind
+	title string
+}{
+	{AddedCommand, "New commands"},
+	{RemovedCommand, "Removed commands"},
+	{ChangedCommandUsage, "Changed command usages"},
+	{AddedOption, "New options"},
+	{RemovedOption, "Removed options"},
+	{DeprecatedOption, "Deprecated options"},
+	{ChangedDefault, "Changed default values"},
+	{ChangedType, "Changed types"},
+	{ChangedUsage, "Changed option usages"},
+}
+
+// Change is a difference between two versions of a command or an option
+type Change struct {
+	Kind    ChangeKind
+	Command string
+	Option  string
+	From    string
+	To      string
+}
+
+// Changelog contains the changes between two versions
+type Changelog struct {
+	From    string
+	To      string
+	Changes []Change
+}
+
+// snapshot contains the commands of a version, with their usage and options, by name
+type snapshot map[string]*snapshotCommand
+
+type snapshotCommand struct {
+	Usage   string
+	Options map[string]Option
+}
+
+// readSnapshot reads the commands of a version from a toc.yaml file or from a spec file.
+// The options read from a ToC file only have the type, usage and default value defined in the ToC
+func readSnapshot(filename string) (snapshot, error) {
+	contents, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read yaml file %s: %v", filename, err)
+	}
+
+	var keys map[string]interface{}
+	if err := yaml.Unmarshal(contents, &keys); err != nil {
+		return nil, fmt.Errorf("failed to parse yaml file %s: %v", filename, err)
+	}
+	if _, found := keys["categories"]; found {
+		toc, err := ReadToC(filename)
+		if err != nil {
+			return nil, err
+		}
+		return newSnapshotFromToC(toc), nil
+	}
+
+	spec, err := ReadSpec(filename)
+	if err != nil {
+		return nil, err
+	}
+	return newSnapshotFromSpec(spec, nil), nil
+}
+
+func newSnapshotFromToC(toc *ToC) snapshot {
+	result := snapshot{}
+	for _, category := range toc.Categories {
+		for _, tocCommand := range category.Commands {
+			command := &snapshotCommand{
+				Usage:   tocCommand.Usage,
+				Options: map[string]Option{},
+			}
+			for _, group := range tocCommand.OptionsGroups {
+				for _, tocOption := range group.Options {
+					option := Option{Name: tocOption.Name}
+					command.Options[tocOption.Name] = option.WithToC(&tocOption)
+				}
+			}
+			result[tocCommand.Name] = command
+		}
+	}
+	return result
+}
+
+// newSnapshotFromSpec returns the visible commands of the spec and their own options.
+// If toc is not nil, only the commands of the ToC are returned, with the overrides of the ToC
+// applied, and with the inherited options listed in the ToC, as in the snapshot of a ToC file
+func newSnapshotFromSpec(spec *KubectlSpec, toc *ToC) snapshot {
+	result := snapshot{}
+	for _, name := range spec.GetAllCommandNames() {
+		command := spec.GetCommand(name)
+		var tocCommand *ToCCommand
+		if toc != nil {
+			_, tocCommand = toc.GetCommand(name)
+		}
+		if command.Hidden || (toc != nil && tocCommand == nil) {
+			continue
+		}
+		snapshotCmd := &snapshotCommand{
+			Usage:   command.Usage,
+			Options: map[string]Option{},
+		}
+		for _, option := range command.Options.visible(false) {
+			snapshotCmd.Options[option.Name] = *option
+		}
+		if tocCommand != nil {
+			for _, group := range tocCommand.OptionsGroups {
+				if group.inlined {
+					continue
+				}
+				for i := range group.Options {
+					if option := command.FindOption(group.Options[i].Name); option != nil {
+						snapshotCmd.Options[option.Name] = option.WithToC(&group.Options[i])
+					}
+				}
+			}
+		}
+		result[name] = snapshotCmd
+	}
+	return result
+}
+
+// Diff returns the changes from a version to another, read from toc.yaml or spec files
+func Diff(fromFile string, toFile string) (*Changelog, error) {
+	from, err := readSnapshot(fromFile)
+	if err != nil {
+		return nil, err
+	}
+	to, err := readSnapshot(toFile)
+	if err != nil {
+		return nil, err
+	}
+	return newChangelog(versionLabel(fromFile), versionLabel(toFile), from, to), nil
+}
+
+// versionLabel returns the name of the directory containing the file, as the name of its version
+func versionLabel(filename string) string {
+	return filepath.Base(filepath.Dir(filename))
+}
+
+func newChangelog(fromLabel string, toLabel string, from snapshot, to snapshot) *Changelog {
+	result := &Changelog{
+		From: fromLabel,
+		To:   toLabel,
+	}
+
+	for _, name := range sortedCommandNames(from, to) {
+		fromCmd, inFrom := from[name]
+		toCmd, inTo := to[name]
+		switch {
+		case !inFrom:
+			result.Changes = append(result.Changes, Change{Kind: AddedCommand, Command: name})
+			continue
+		case !inTo:
+			result.Changes = append(result.Changes, Change{Kind: RemovedCommand, Command: name})
+			continue
+		}
+
+		if changed(fromCmd.Usage, toCmd.Usage) {
+			result.Changes = append(result.Changes, Change{Kind: ChangedCommandUsage, Command: name, From: fromCmd.Usage, To: toCmd.Usage})
+		}
+
+		for _, optName := range sortedOptionNames(fromCmd, toCmd) {
+			fromOpt, inFrom := fromCmd.Options[optName]
+			toOpt, inTo := toCmd.Options[optName]
+			switch {
+			case !inFrom:
+				result.Changes = append(result.Changes, Change{Kind: AddedOption, Command: name, Option: optName})
+				continue
+			case !inTo:
+				result.Changes = append(result.Changes, Change{Kind: RemovedOption, Command: name, Option: optName})
+				continue
+			}
+			if len(fromOpt.Deprecated) == 0 && len(toOpt.Deprecated) > 0 {
+				result.Changes = append(result.Changes, Change{Kind: DeprecatedOption, Command: name, Option: optName, To: toOpt.Deprecated})
+			}
+			if changed(fromOpt.DefaultValue, toOpt.DefaultValue) {
+				result.Changes = append(result.Changes, Change{Kind: ChangedDefault, Command: name, Option: optName, From: fromOpt.DefaultValue, To: toOpt.DefaultValue})
+			}
+			if changed(fromOpt.Type, toOpt.Type) {
+				result.Changes = append(result.Changes, Change{Kind: ChangedType, Command: name, Option: optName, From: fromOpt.Type, To: toOpt.Type})
+			}
+			if changed(fromOpt.Usage, toOpt.Usage) {
+				result.Changes = append(result.Changes, Change{Kind: ChangedUsage, Command: name, Option: optName, From: fromOpt.Usage, To: toOpt.Usage})
+			}
+		}
+	}
+	return result
+}
+
+// changed returns true if a value known in both versions is different
+func changed(from string, to string) bool {
+	return len(from) > 0 && len(to) > 0 && from != to
+}
+
+func sortedCommandNames(from snapshot, to snapshot) []string {
+	names := map[string]struct{}{}
+	for name := range from {
+		names[name] = struct{}{}
+	}
+	for name := range to {
+		names[name] = struct{}{}
+	}
+	return sortedKeys(names)
+}
+
+func sortedOptionNames(from *snapshotCommand, to *snapshotCommand) []string {
+	names := map[string]struct{}{}
+	for name := range from.Options {
+		names[name] = struct{}{}
+	}
+	for name := range to.Options {
+		names[name] = struct{}{}
+	}
+	return sortedKeys(names)
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	result := make([]string, 0, len(m))
+	for key := range m {
+		result = append(result, key)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// describe returns a sentence describing the change, translated in the language of opts,
+// using the given functions to format inline code and text
+func (o *Change) describe(opts *GenerateOptions, code func(string) string, text func(string) string) string {
+	switch o.Kind {
+	case AddedCommand:
+		return fmt.Sprintf(opts.T("new command %s"), code(o.Command))
+	case RemovedCommand:
+		return fmt.Sprintf(opts.T("removed command %s"), code(o.Command))
+	case ChangedCommandUsage:
+		return fmt.Sprintf(opts.T("usage of %s changed from %s to %s"), code(o.Command), code(o.From), code(o.To))
+	case AddedOption:
+		return fmt.Sprintf(opts.T("new option %s in %s"), code(o.Option), code(o.Command))
+	case RemovedOption:
+		return fmt.Sprintf(opts.T("removed option %s from %s"), code(o.Option), code(o.Command))
+	case DeprecatedOption:
+		return fmt.Sprintf(opts.T("option %s of %s is deprecated: %s"), code(o.Option), code(o.Command), text(o.To))
+	case ChangedDefault:
+		return fmt.Sprintf(opts.T("default value of option %s in %s changed from %s to %s"), code(o.Option), code(o.Command), code(o.From), code(o.To))
+	case ChangedType:
+		return fmt.Sprintf(opts.T("type of option %s in %s changed from %s to %s"), code(o.Option), code(o.Command), code(o.From), code(o.To))
+	case ChangedUsage:
+		return fmt.Sprintf(opts.T("usage of option %s in %s changed to: %s"), code(o.Option), code(o.Command), text(strings.Join(strings.Fields(o.To), " ")))
+	}
+	return ""
+}
+
+func (o *Changelog) changesOfKind(kind ChangeKind) (changes []Change) {
+	for _, change := range o.Changes {
+		if change.Kind == kind {
+			changes = append(changes, change)
+		}
+	}
+	return
+}
+
+// AsMarkdown writes the changelog as a Markdown section, translated in the language of opts
+func (o *Changelog) AsMarkdown(w io.Writer, opts *GenerateOptions) {
+	fmt.Fprintf(w, "## %s\n", fmt.Sprintf(opts.T("Changes from %s to %s"), o.From, o.To))
+	markdownCode := func(s string) string { return "`" + s + "`" }
+	markdownText := func(s string) string { return s }
+	for _, kind := range changeKinds {
+		changes := o.changesOfKind(kind.kind)
+		if len(changes) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "\n### %s\n\n", opts.T(kind.title))
+		for _, change := range changes {
+			fmt.Fprintf(w, "- %s\n", change.describe(opts, markdownCode, markdownText))
+		}
+	}
+}
+
+// AsDocbook writes the changelog as a DocBook appendix, translated in the language of opts
+func (o *Changelog) AsDocbook(w io.Writer, opts *GenerateOptions) {
+	fmt.Fprintf(w, `  <appendix id="changelog">
+    <title>%s</title>
+`, escapeXml(fmt.Sprintf(opts.T("Changes from %s to %s"), o.From, o.To)))
+	docbookCode := func(s string) string { return "<literal>" + escapeXml(s) + "</literal>" }
+	for _, kind := range changeKinds {
+		changes := o.changesOfKind(kind.kind)
+		if len(changes) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "    <bridgehead renderas=\"sect2\">%s</bridgehead>\n", escapeXml(opts.T(kind.title)))
+		fmt.Fprint(w, "    <itemizedlist>\n")
+		for _, change := range changes {
+			fmt.Fprintf(w, "      <listitem><para>%s</para></listitem>\n", change.describe(opts, docbookCode, escapeXml))
+		}
+		fmt.Fprint(w, "    </itemizedlist>\n")
+	}
+	if len(o.Changes) == 0 {
+		fmt.Fprintf(w, "    <para>%s</para>\n", escapeXml(opts.T("No changes.")))
+	}
+	fmt.Fprint(w, "  </appendix>\n")
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2019 Philippe Martin.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChanged(t *testing.T) {
+	tests := []struct {
+		from string
+		to   string
+		want bool
+	}{
+		{from: "", to: "", want: false},
+		{from: "1", to: "", want: false},
+		{from: "", to: "1", want: false},
+		{from: "1", to: "1", want: false},
+		{from: "1", to: "2", want: true},
+	}
+	for _, tt := range tests {
+		if got := changed(tt.from, tt.to); got != tt.want {
+			t.Errorf("changed(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestNewChangelog(t *testing.T) {
+	command := func(usage string, options ...Option) *snapshotCommand {
+		result := &snapshotCommand{Usage: usage, Options: map[string]Option{}}
+		for _, option := range options {
+			result.Options[option.Name] = option
+		}
+		return result
+	}
+	tests := []struct {
+		name string
+		from snapshot
+		to   snapshot
+		want []Change
+	}{
+		{
+			name: "no change",
+			from: snapshot{"get": command("get TYPE", Option{Name: "output", Type: "string"})},
+			to:   snapshot{"get": command("get TYPE", Option{Name: "output", Type: "string"})},
+		},
+		{
+			name: "added and removed commands",
+			from: snapshot{"get": command(""), "run": command("")},
+			to:   snapshot{"apply": command(""), "get": command("")},
+			want: []Change{
+				{Kind: AddedCommand, Command: "apply"},
+				{Kind: RemovedCommand, Command: "run"},
+			},
+		},
+		{
+			name: "changed usage of the command",
+			from: snapshot{"get": command("get TYPE")},
+			to:   snapshot{"get": command("get TYPE NAME")},
+			want: []Change{
+				{Kind: ChangedCommandUsage, Command: "get", From: "get TYPE", To: "get TYPE NAME"},
+			},
+		},
+		{
+			name: "usage unknown in a version",
+			from: snapshot{"get": command("", Option{Name: "output"})},
+			to:   snapshot{"get": command("get TYPE", Option{Name: "output", Type: "string", DefaultValue: "table"})},
+		},
+		{
+			name: "added and removed options",
+			from: snapshot{"get": command("", Option{Name: "output"}, Option{Name: "export"})},
+			to:   snapshot{"get": command("", Option{Name: "output"}, Option{Name: "watch"})},
+			want: []Change{
+				{Kind: RemovedOption, Command: "get", Option: "export"},
+				{Kind: AddedOption, Command: "get", Option: "watch"},
+			},
+		},
+		{
+			name: "changed options",
+			from: snapshot{"get": command("", Option{Name: "output", Type: "string", DefaultValue: "table", Usage: "Output format."})},
+			to:   snapshot{"get": command("", Option{Name: "output", Type: "stringArray", DefaultValue: "wide", Usage: "Output format.", Deprecated: "use --format"})},
+			want: []Change{
+				{Kind: DeprecatedOption, Command: "get", Option: "output", To: "use --format"},
+				{Kind: ChangedDefault, Command: "get", Option: "output", From: "table", To: "wide"},
+				{Kind: ChangedType, Command: "get", Option: "output", From: "string", To: "stringArray"},
+			},
+		},
+		{
+			name: "still deprecated option",
+			from: snapshot{"get": command("", Option{Name: "output", Deprecated: "use --format"})},
+			to:   snapshot{"get": command("", Option{Name: "output", Deprecated: "use --format"})},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changelog := newChangelog("v1", "v2", tt.from, tt.to)
+			if !reflect.DeepEqual(changelog.Changes, tt.want) {
+				t.Errorf("got %+v, want %+v", changelog.Changes, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewSnapshotFromSpec(t *testing.T) {
+	spec := hiddenTestSpec()
+	spec.TopLevelCommandGroups[0].Commands = append(spec.TopLevelCommandGroups[0].Commands, TopLevelCommand{
+		MainCommand: &Command{Name: "run", Options: Options{{Name: "image", Type: "string"}}},
+	})
+	usage := "Namespace of the request."
+	toc := &ToC{
+		Categories: []*Category{{
+			Name: "Basic Commands",
+			Commands: []*ToCCommand{{
+				Name: "get",
+				OptionsGroups: []OptionsGroup{
+					{Name: "Options", Options: []ToCOption{{Name: "output"}, {Name: "namespace", Usage: &usage}}},
+					{Name: "Global options", Options: []ToCOption{{Name: "profile"}}, inlined: true},
+				},
+			}},
+		}},
+	}
+	tests := []struct {
+		name string
+		toc  *ToC
+		want map[string][]string
+	}{
+		{
+			name: "without ToC",
+			want: map[string][]string{
+				"get": {"output", "record"},
+				"run": {"image"},
+			},
+		},
+		{
+			name: "with ToC",
+			toc:  toc,
+			want: map[string][]string{
+				"get": {"namespace", "output", "record"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := map[string][]string{}
+			for name, command := range newSnapshotFromSpec(spec, tt.toc) {
+				got[name] = sortedOptionNames(command, command)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if got := newSnapshotFromSpec(spec, toc)["get"].Options["namespace"].Usage; got != usage {
+		t.Errorf("got usage %q, want %q", got, usage)
+	}
+}
@@ -20,30 +20,28 @@ import (
 	"encoding/xml"
 	"fmt"
 	"io"
-	"os"
 	"strings"
-
-	"github.com/jinzhu/copier"
 )
 
-func (o *Command) AsDocbook(w io.Writer, config *ToCCommand) {
-	refname := o.Name
-	if len(o.Path) > 0 {
-		refname = strings.Replace(o.Path, "/", " ", 1) + " " + refname
-	}
+func (o *Command) AsDocbook(w io.Writer, toc *ToC, config *ToCCommand, opts *GenerateOptions) error {
+	refname := o.GetRefName()
 	refpurpose := o.Synopsis
-	fmt.Fprintf(w, `    <refentry>
+	fmt.Fprintf(w, `    <refentry id="%s">
       <refnamediv>
         <refname>%s</refname>
-
+`, commandID(config.Name), refname)
+	for _, alias := range o.GetAliasRefNames() {
+		fmt.Fprintf(w, "        <refname>%s</refname>\n", alias)
+	}
+	fmt.Fprintf(w, `
         <refpurpose>%s</refpurpose>
       </refnamediv>
 
-      <refsynopsisdiv><title>Usage</title>
+      <refsynopsisdiv><title>%s</title>
 
         <cmdsynopsis>
-          <command>kubectl %s</command>
-`, refname, refpurpose, refname)
+          <command>%s</command>
+`, refpurpose, opts.T("Usage"), o.GetCommandLine(opts.GetBinary()))
 
 	for _, arg := range config.Args {
 		if !arg.End {
@@ -54,13 +52,9 @@ func (o *Command) AsDocbook(w io.Writer, config *ToCCommand) {
 
 	for _, group := range config.OptionsGroups {
 		for _, tocOption := range group.Options {
-			option := o.GetOption(tocOption.Name)
+			option := o.FindOption(tocOption.Name)
 			if option == nil {
-				option = o.GetInheritedOption(tocOption.Name)
-				if option == nil {
-					fmt.Printf("option %s of command %s not found\n", tocOption.Name, o.Name)
-					os.Exit(1)
-				}
+				return &OptionNotFoundError{Command: config.Name, Option: tocOption.Name}
 			}
 			option.AsDocbook(w, &tocOption)
 		}
@@ -77,32 +71,33 @@ func (o *Command) AsDocbook(w io.Writer, config *ToCCommand) {
       </refsynopsisdiv>
 `)
 
-	if *ShowUsage {
+	if opts.ShowUsage {
 		// Description
-		fmt.Fprintf(w, `      <refsection><title>Original Usage</title>
+		fmt.Fprintf(w, `      <refsection><title>%s</title>
         <programlisting>%s</programlisting></refsection>
-`, escapeXml(o.Usage))
+`, opts.T("Original Usage"), escapeXml(o.Usage))
 
 	}
 
 	// Description
-	fmt.Fprint(w, `      <refsection>
-        <title>Description</title>
-`)
-
-	desc := o.Description
-	paras := strings.Split(desc, "\n\n")
-	for _, para := range paras {
-		fmt.Fprintf(w, "          <para>%s</para>\n", escapeXml(para))
+	fmt.Fprintf(w, `      <refsection>
+        <title>%s</title>
+`, opts.T("Description"))
+	indexTermAsDocbook(w, "          ", commandIndex, refname, "")
+	if len(o.Deprecated) > 0 {
+		fmt.Fprintf(w, "          <warning><para>%s</para></warning>\n", fmt.Sprintf(opts.T("This command is deprecated: %s"), escapeXml(o.Deprecated)))
 	}
+
+	descriptionToDocbook(w, o.Description, "          ")
 	fmt.Fprint(w, `      </refsection>
 `)
 
 	// Options
-	if len(config.OptionsGroups) > 0 {
-		fmt.Fprint(w, `      <refsection>
-        <title>Options</title>
-`)
+	inherited := o.InheritedOptions.visible(opts.ShowHidden)
+	if len(config.OptionsGroups) > 0 || len(inherited) > 0 {
+		fmt.Fprintf(w, `      <refsection>
+        <title>%s</title>
+`, opts.T("Options"))
 
 		for _, group := range config.OptionsGroups {
 			if len(group.Name) > 0 {
@@ -110,32 +105,36 @@ func (o *Command) AsDocbook(w io.Writer, config *ToCCommand) {
 			}
 			fmt.Fprintf(w, "        <variablelist>\n")
 			for _, tocOption := range group.Options {
-				option := o.GetOption(tocOption.Name)
+				option := o.FindOption(tocOption.Name)
 				if option == nil {
-					option = o.GetInheritedOption(tocOption.Name)
-					if option == nil {
-						fmt.Printf("option %s of command %s not found\n", tocOption.Name, o.Name)
-						os.Exit(1)
-					}
+					return &OptionNotFoundError{Command: config.Name, Option: tocOption.Name}
 				}
-				option.AsDocbookDetails(w, &tocOption)
+				option.AsDocbookDetails(w, optionID(config.Name, tocOption.Name), refname, &tocOption, opts)
 			}
 			fmt.Fprintf(w, "        </variablelist>\n")
 		}
 
+		if len(inherited) > 0 {
+			xref := fmt.Sprintf("<xref linkend=\"%s\"/>", globalOptionsID)
+			fmt.Fprintf(w, "        <para>%s</para>\n", fmt.Sprintf(opts.T("The global options are also accepted, see %s."), xref))
+		}
+
 		fmt.Fprint(w, `      </refsection>
 `)
 	}
 
 	// Examples
 	if len(o.Examples) > 0 {
-		fmt.Fprint(w, `      <refsection>
-        <title>Examples</title>
-`)
+		fmt.Fprintf(w, `      <refsection>
+        <title>%s</title>
+`, opts.T("Examples"))
+		for _, resource := range o.GetExampleResources() {
+			indexTermAsDocbook(w, "          ", resourceIndex, resource, refname)
+		}
 		for _, example := range o.Examples {
-			fmt.Fprintf(w, "          <para>%s</para>\n", example.Title)
+			fmt.Fprintf(w, "          <para>%s</para>\n", escapeXml(example.Title))
 			fmt.Fprint(w, "          <programlisting>")
-			fmt.Fprintf(w, "%s", example.Content)
+			example.AsDocbook(w)
 			fmt.Fprint(w, "</programlisting>\n")
 
 		}
@@ -143,8 +142,23 @@ func (o *Command) AsDocbook(w io.Writer, config *ToCCommand) {
 `)
 	}
 
+	// See also
+	if seeAlso := o.GetSeeAlso(config, toc); len(seeAlso) > 0 {
+		fmt.Fprintf(w, `      <refsection>
+        <title>%s</title>
+        <simplelist type="inline">
+`, opts.T("See also"))
+		for _, name := range seeAlso {
+			fmt.Fprintf(w, "          <member><xref linkend=\"%s\"/></member>\n", commandID(name))
+		}
+		fmt.Fprint(w, `        </simplelist>
+      </refsection>
+`)
+	}
+
 	fmt.Fprint(w, `    </refentry>
 `)
+	return nil
 }
 
 func (o *Arg) AsDocbook(w io.Writer) {
@@ -160,21 +174,7 @@ func (o *Arg) AsDocbook(w io.Writer) {
 }
 
 func (op *Option) AsDocbook(w io.Writer, config *ToCOption) {
-	var o Option
-	copier.Copy(&o, op)
-
-	if config.Type != nil {
-		o.Type = *config.Type
-	}
-	if config.Usage != nil {
-		o.Usage = *config.Usage
-	}
-	if config.Shorthand != nil {
-		o.Shorthand = *config.Shorthand
-	}
-	if config.Default != nil {
-		o.DefaultValue = *config.Default
-	}
+	o := op.WithToC(config)
 	choice := "opt"
 	if config.Required {
 		choice = "plain"
@@ -200,6 +200,13 @@ func (op *Option) AsDocbook(w io.Writer, config *ToCOption) {
 
 	case "string":
 		value := optionName + "<replaceable>value</replaceable>"
+		if values := op.GetValues(config); len(values) > 0 {
+			value = optionName + "<group choice=\"req\">"
+			for _, v := range values {
+				value += "<arg choice=\"plain\">" + escapeXml(v) + "</arg>"
+			}
+			value += "</group>"
+		}
 		fmt.Fprintf(w, "          <arg choice=\"%s\">%s</arg>\n", choice, value)
 
 	case "int32", "int64", "int", "duration":
@@ -230,25 +237,10 @@ func (op *Option) AsDocbook(w io.Writer, config *ToCOption) {
 	}
 }
 
-func (op *Option) AsDocbookDetails(w io.Writer, config *ToCOption) {
-
-	var o Option
-	copier.Copy(&o, op)
+func (op *Option) AsDocbookDetails(w io.Writer, id string, refname string, config *ToCOption, opts *GenerateOptions) {
+	o := op.WithToC(config)
 
-	if config.Type != nil {
-		o.Type = *config.Type
-	}
-	if config.Usage != nil {
-		o.Usage = *config.Usage
-	}
-	if config.Shorthand != nil {
-		o.Shorthand = *config.Shorthand
-	}
-	if config.Default != nil {
-		o.DefaultValue = *config.Default
-	}
-
-	fmt.Fprintf(w, "          <varlistentry>\n")
+	fmt.Fprintf(w, "          <varlistentry id=\"%s\">\n", id)
 	fmt.Fprintf(w, "            <term>")
 
 	value := "--" + o.Name
@@ -259,16 +251,63 @@ func (op *Option) AsDocbookDetails(w io.Writer, config *ToCOption) {
 
 	var def string
 	if len(o.DefaultValue) > 0 && o.DefaultValue != "[]" {
-		def = fmt.Sprintf(", defaults to %s", o.DefaultValue)
+		def = ", " + fmt.Sprintf(opts.T("defaults to %s"), o.DefaultValue)
+	}
+	var deprecated string
+	if len(o.Deprecated) > 0 {
+		deprecated = ", " + opts.T("deprecated")
 	}
-	fmt.Fprintf(w, " (%s%s)</term>\n", o.Type, def)
-	fmt.Fprintf(w, "            <listitem><para>%s</para></listitem>\n", escapeXml(o.Usage))
+	fmt.Fprintf(w, " (%s%s%s)</term>\n", o.Type, def, deprecated)
+	fmt.Fprint(w, "            <listitem>\n")
+	indexTermAsDocbook(w, "              ", optionIndex, "--"+o.Name, refname)
+	if note := o.GetDeprecationNote(opts); len(note) > 0 {
+		fmt.Fprintf(w, "              <para><emphasis role=\"bold\">%s</emphasis></para>\n", escapeXml(note))
+	}
+	fmt.Fprintf(w, "              <para>%s</para>\n", escapeXml(o.Usage))
+	if values := op.GetValues(config); len(values) > 0 {
+		fmt.Fprintf(w, `              <informaltable>
+                <tgroup cols="1">
+                  <thead><row><entry>%s</entry></row></thead>
+                  <tbody>
+`, opts.T("Value"))
+		for _, v := range values {
+			var def string
+			if v == o.DefaultValue {
+				def = " " + opts.T("(default)")
+			}
+			fmt.Fprintf(w, "                    <row><entry><literal>%s</literal>%s</entry></row>\n", escapeXml(v), def)
+		}
+		fmt.Fprint(w, `                  </tbody>
+                </tgroup>
+              </informaltable>
+`)
+	}
+	fmt.Fprint(w, "            </listitem>\n")
 	fmt.Fprintf(w, "          </varlistentry>\n")
 }
 
+// GlobalOptionsAsDocbook writes the appendix documenting the global options, if any
+func (o *KubectlSpec) GlobalOptionsAsDocbook(w io.Writer, opts *GenerateOptions) {
+	options := o.GetGlobalOptions(opts.ShowHidden)
+	if len(options) == 0 {
+		return
+	}
+	fmt.Fprintf(w, `  <appendix id="%s"><title>%s</title>
+    <para>%s</para>
+    <variablelist>
+`, globalOptionsID, opts.T("Global options"), opts.T("The following options can be passed to any command."))
+	for _, option := range options {
+		option.AsDocbookDetails(w, globalOptionID(option.Name), opts.T("global option"), &ToCOption{}, opts)
+	}
+	fmt.Fprint(w, `    </variablelist>
+  </appendix>
+`)
+}
+
 func escapeXml(s string) string {
 	var b []byte
 	buf := bytes.NewBuffer(b)
 	xml.EscapeText(buf, []byte(s))
-	return buf.String()
+	// keep the new lines readable
+	return strings.ReplaceAll(buf.String(), "&#xA;", "\n")
 }
@@ -0,0 +1,41 @@
+/*
+Copyright 2019 Philippe Martin.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import "fmt"
+
+// CommandNotFoundError is returned when a command of the ToC is not part of the kubectl command tree
+type CommandNotFoundError struct {
+	// Command is the name of the command in the ToC (e.g. create/deployment)
+	Command string
+}
+
+func (e *CommandNotFoundError) Error() string {
+	return fmt.Sprintf("command %s not found", e.Command)
+}
+
+// OptionNotFoundError is returned when an option of the ToC is not an option of the command,
+// nor an inherited option
+type OptionNotFoundError struct {
+	// Command is the name of the command in the ToC (e.g. create/deployment)
+	Command string
+	Option  string
+}
+
+func (e *OptionNotFoundError) Error() string {
+	return fmt.Sprintf("option %s of command %s not found", e.Option, e.Command)
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2019 Philippe Martin.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// ExampleLineKind is the kind of a line of an example
+type ExampleLineKind string
+
+const (
+	// ExampleCommand is the first line of a command
+	ExampleCommand ExampleLineKind = "command"
+	// ExampleContinuation is a line continuing a command, after a backslash or in a heredoc
+	ExampleContinuation ExampleLineKind = "continuation"
+	// ExampleOutput is a line of the expected output of a command
+	ExampleOutput ExampleLineKind = "output"
+)
+
+// ExampleLine is a line of an example
+type ExampleLine struct {
+	Kind ExampleLineKind
+	// Prompt is the prompt preceding a command in the example (e.g. "$ "), if any
+	Prompt string
+	Text   string
+}
+
+// examplePrompt is the prompt identifying the commands, when an example shows the output of its commands
+const examplePrompt = "$ "
+
+// heredocRegexp matches the start of a heredoc (e.g. <<EOF, <<-'EOF'), but not a here-string (<<<)
+var heredocRegexp = regexp.MustCompile(`(?:^|[^<])<<-?\s*['"]?([A-Za-z_][A-Za-z0-9_]*)['"]?`)
+
+// exampleScanner follows the commands of an example, to know if a line continues the previous command
+type exampleScanner struct {
+	heredoc   string
+	continued bool
+}
+
+// inCommand returns true if the next line continues the current command
+func (o *exampleScanner) inCommand() bool {
+	return len(o.heredoc) > 0 || o.continued
+}
+
+// scan updates the state of the scanner with a line of a command
+func (o *exampleScanner) scan(line string) {
+	trimmed := strings.TrimSpace(line)
+	if len(o.heredoc) > 0 {
+		if trimmed == o.heredoc {
+			o.heredoc = ""
+		}
+		return
+	}
+	o.continued = strings.HasSuffix(trimmed, "\\")
+	if matches := heredocRegexp.FindStringSubmatch(trimmed); matches != nil {
+		o.heredoc = matches[1]
+	}
+}
+
+// GetLines returns the lines of the example, with their kind. When some commands
+// are preceded by a prompt, the lines without a prompt are the output of the commands
+func (o Example) GetLines() (result []ExampleLine) {
+	lines := strings.Split(o.Content, "\n")
+	hasPrompt := false
+	for _, line := range lines {
+		if strings.HasPrefix(line, examplePrompt) {
+			hasPrompt = true
+			break
+		}
+	}
+
+	scanner := exampleScanner{}
+	for _, line := range lines {
+		switch {
+		case scanner.inCommand():
+			result = append(result, ExampleLine{Kind: ExampleContinuation, Text: line})
+		case hasPrompt && !strings.HasPrefix(line, examplePrompt):
+			result = append(result, ExampleLine{Kind: ExampleOutput, Text: line})
+			continue
+		case hasPrompt:
+			result = append(result, ExampleLine{Kind: ExampleCommand, Prompt: examplePrompt, Text: strings.TrimPrefix(line, examplePrompt)})
+		default:
+			result = append(result, ExampleLine{Kind: ExampleCommand, Text: line})
+		}
+		scanner.scan(line)
+	}
+	return
+}
+
+// positions of SplitExamples in the examples
+const (
+	start = iota
+	title
+	content
+)
+
+// SplitExamples splits the examples of a command, made of commands preceded by "# title" lines.
+// The indentation inside an example is preserved, and the lines continuing a command
+// (after a backslash or in a heredoc) are part of the command, even if they start with #
+func SplitExamples(examples string) (result []Example) {
+	var current Example
+	var contentLines []string
+	scanner := exampleScanner{}
+	pos := start
+
+	flush := func() {
+		for len(contentLines) > 0 && len(strings.TrimSpace(contentLines[len(contentLines)-1])) == 0 {
+			contentLines = contentLines[:len(contentLines)-1]
+		}
+		current.Content = dedent(contentLines)
+		if len(current.Title) > 0 || len(current.Content) > 0 {
+			result = append(result, current)
+		}
+		current = Example{}
+		contentLines = nil
+		scanner = exampleScanner{}
+	}
+
+	for _, line := range strings.Split(examples, "\n") {
+		line = strings.TrimRight(line, " \t")
+		trimmed := strings.TrimSpace(line)
+		if !scanner.inCommand() {
+			if len(trimmed) == 0 {
+				continue
+			}
+			if trimmed[0] == '#' {
+				if pos == title {
+					current.Title += "\n " + strings.TrimLeft(trimmed, "# ")
+				} else {
+					flush()
+					current.Title = strings.TrimLeft(trimmed, "# ")
+					pos = title
+				}
+				continue
+			}
+		}
+		contentLines = append(contentLines, line)
+		scanner.scan(line)
+		pos = content
+	}
+	flush()
+	return
+}
+
+// AsDocbook writes the lines of the example, the prompts, commands and output marked up
+func (o Example) AsDocbook(w io.Writer) {
+	for i, line := range o.GetLines() {
+		if i > 0 {
+			fmt.Fprint(w, "\n")
+		}
+		if len(line.Text) == 0 && len(line.Prompt) == 0 {
+			continue
+		}
+		switch line.Kind {
+		case ExampleOutput:
+			fmt.Fprintf(w, "<computeroutput>%s</computeroutput>", escapeXml(line.Text))
+		default:
+			if len(line.Prompt) > 0 {
+				fmt.Fprintf(w, "<prompt>%s</prompt>", escapeXml(line.Prompt))
+			}
+			fmt.Fprintf(w, "<userinput>%s</userinput>", escapeXml(line.Text))
+		}
+	}
+}
+
+// dedent joins the lines, after removing the indentation common to all the lines
+func dedent(lines []string) string {
+	indent := -1
+	for _, line := range lines {
+		if len(strings.TrimSpace(line)) == 0 {
+			continue
+		}
+		n := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent < 0 || n < indent {
+			indent = n
+		}
+	}
+	result := make([]string, len(lines))
+	for i, line := range lines {
+		if len(line) >= indent && indent > 0 {
+			line = line[indent:]
+		}
+		result[i] = line
+	}
+	return strings.Join(result, "\n")
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2019 Philippe Martin.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitExamples(t *testing.T) {
+	tests := []struct {
+		name     string
+		examples string
+		want     []Example
+	}{
+		{
+			name: "titles and commands",
+			examples: `  # List all pods
+  kubectl get pods
+
+  # List a single pod
+  # in wide output format
+  kubectl get pod web -o wide`,
+			want: []Example{
+				{Title: "List all pods", Content: "kubectl get pods"},
+				{Title: "List a single pod\n in wide output format", Content: "kubectl get pod web -o wide"},
+			},
+		},
+		{
+			name: "heredoc body with # lines",
+			examples: `  # Create a config map from a heredoc
+  kubectl apply -f - <<EOF
+  # a comment of the manifest
+  apiVersion: v1
+  kind: ConfigMap
+  EOF
+
+  # Next example
+  kubectl get cm`,
+			want: []Example{
+				{Title: "Create a config map from a heredoc", Content: "kubectl apply -f - <<EOF\n# a comment of the manifest\napiVersion: v1\nkind: ConfigMap\nEOF"},
+				{Title: "Next example", Content: "kubectl get cm"},
+			},
+		},
+		{
+			name: "quoted and indented heredoc delimiter",
+			examples: `  # Quoted delimiter
+  cat <<-'END' | kubectl apply -f -
+    # kept
+  END`,
+			want: []Example{
+				{Title: "Quoted delimiter", Content: "cat <<-'END' | kubectl apply -f -\n  # kept\nEND"},
+			},
+		},
+		{
+			name: "backslash continuations",
+			examples: `  # Create a secret
+  kubectl create secret generic my-secret \
+    --from-literal=key1=supersecret \
+  # not a title, the command continues
+  kubectl get secrets`,
+			want: []Example{
+				{Title: "Create a secret", Content: "kubectl create secret generic my-secret \\\n  --from-literal=key1=supersecret \\\n# not a title, the command continues\nkubectl get secrets"},
+			},
+		},
+		{
+			name: "here-string is not a heredoc",
+			examples: `  # Here-string
+  kubectl apply -f - <<< "$MANIFEST"
+  # Next example
+  kubectl get pods`,
+			want: []Example{
+				{Title: "Here-string", Content: `kubectl apply -f - <<< "$MANIFEST"`},
+				{Title: "Next example", Content: "kubectl get pods"},
+			},
+		},
+		{
+			name:     "empty",
+			examples: "\n  \n",
+			want:     nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SplitExamples(tt.examples); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExampleGetLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []ExampleLine
+	}{
+		{
+			name:    "commands without prompt",
+			content: "kubectl get pods\nkubectl get nodes",
+			want: []ExampleLine{
+				{Kind: ExampleCommand, Text: "kubectl get pods"},
+				{Kind: ExampleCommand, Text: "kubectl get nodes"},
+			},
+		},
+		{
+			name:    "prompts with output",
+			content: "$ kubectl get ns\nNAME      STATUS\ndefault   Active\n$ kubectl version",
+			want: []ExampleLine{
+				{Kind: ExampleCommand, Prompt: "$ ", Text: "kubectl get ns"},
+				{Kind: ExampleOutput, Text: "NAME      STATUS"},
+				{Kind: ExampleOutput, Text: "default   Active"},
+				{Kind: ExampleCommand, Prompt: "$ ", Text: "kubectl version"},
+			},
+		},
+		{
+			name:    "prompt with a continued command",
+			content: "$ kubectl run nginx \\\n  --image=nginx\npod/nginx created",
+			want: []ExampleLine{
+				{Kind: ExampleCommand, Prompt: "$ ", Text: "kubectl run nginx \\"},
+				{Kind: ExampleContinuation, Text: "  --image=nginx"},
+				{Kind: ExampleOutput, Text: "pod/nginx created"},
+			},
+		},
+		{
+			name:    "heredoc",
+			content: "kubectl apply -f - <<EOF\n# comment\nkind: Pod\nEOF\nkubectl get pods",
+			want: []ExampleLine{
+				{Kind: ExampleCommand, Text: "kubectl apply -f - <<EOF"},
+				{Kind: ExampleContinuation, Text: "# comment"},
+				{Kind: ExampleContinuation, Text: "kind: Pod"},
+				{Kind: ExampleContinuation, Text: "EOF"},
+				{Kind: ExampleCommand, Text: "kubectl get pods"},
+			},
+		},
+		{
+			name:    "dollar without space is not a prompt",
+			content: "kubectl get pods -l app=$APP\n$HOME/bin/kubectl get nodes",
+			want: []ExampleLine{
+				{Kind: ExampleCommand, Text: "kubectl get pods -l app=$APP"},
+				{Kind: ExampleCommand, Text: "$HOME/bin/kubectl get nodes"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (Example{Content: tt.content}).GetLines(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2019 Philippe Martin.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ExportSchemaVersion is the version of the schema of the exported spec.
+// It changes on any incompatible change of the Exported* types.
+const ExportSchemaVersion = "v1"
+
+// ExportedSpec is the spec of all the kubectl commands, merged with the overrides of the ToC
+type ExportedSpec struct {
+	SchemaVersion     string            `json:"schema_version" yaml:"schema_version"`
+	KubernetesVersion string            `json:"kubernetes_version,omitempty" yaml:"kubernetes_version,omitempty"`
+	KubectlVersion    string            `json:"kubectl_version,omitempty" yaml:"kubectl_version,omitempty"`
+	Commands          []ExportedCommand `json:"commands" yaml:"commands"`
+}
+
+// ExportedCommand is a kubectl command. Name is the name used in the ToC (e.g. create/deployment),
+// Command the full command line (e.g. kubectl create deployment).
+// Category is empty if the command is not part of the ToC
+type ExportedCommand struct {
+	Name        string            `json:"name" yaml:"name"`
+	Command     string            `json:"command" yaml:"command"`
+	Category    string            `json:"category,omitempty" yaml:"category,omitempty"`
+	Synopsis    string            `json:"synopsis,omitempty" yaml:"synopsis,omitempty"`
+	Description string            `json:"description,omitempty" yaml:"description,omitempty"`
+	Usage       string            `json:"usage,omitempty" yaml:"usage,omitempty"`
+	Args        []ExportedArg     `json:"args,omitempty" yaml:"args,omitempty"`
+	Options     []ExportedOption  `json:"options,omitempty" yaml:"options,omitempty"`
+	Examples    []ExportedExample `json:"examples,omitempty" yaml:"examples,omitempty"`
+}
+
+// ExportedArg is a positional argument of a command, as defined in the ToC.
+// Choice is one of opt, plain or req, Rep one of norepeat or repeat.
+// End is true for the arguments placed after the options
+type ExportedArg struct {
+	Name   string `json:"name" yaml:"name"`
+	Choice string `json:"choice" yaml:"choice"`
+	Rep    string `json:"rep" yaml:"rep"`
+	End    bool   `json:"end,omitempty" yaml:"end,omitempty"`
+}
+
+// ExportedOption is an option of a command, with its effective type, usage, shorthand and default value.
+// Group is the name of the group of options containing it in the ToC, and is empty
+// if the option is not part of the ToC or is part of an unnamed group
+type ExportedOption struct {
+	Name      string `json:"name" yaml:"name"`
+	Shorthand string `json:"shorthand,omitempty" yaml:"shorthand,omitempty"`
+	Type      string `json:"type" yaml:"type"`
+	Default   string `json:"default,omitempty" yaml:"default,omitempty"`
+	Usage     string `json:"usage,omitempty" yaml:"usage,omitempty"`
+	Required  bool   `json:"required,omitempty" yaml:"required,omitempty"`
+	Inherited bool   `json:"inherited,omitempty" yaml:"inherited,omitempty"`
+	Group     string `json:"group,omitempty" yaml:"group,omitempty"`
+	InToC     bool   `json:"in_toc" yaml:"in_toc"`
+}
+
+// ExportedExample is an example of a command
+type ExportedExample struct {
+	Title   string `json:"title,omitempty" yaml:"title,omitempty"`
+	Content string `json:"content,omitempty" yaml:"content,omitempty"`
+}
+
+// Export writes the spec of the Kubernetes version given in opts, merged with its ToC,
+// to w in the given format (json or yaml)
+func Export(w io.Writer, format string, opts *GenerateOptions) error {
+	toc, _, spec, err := load(opts)
+	if err != nil {
+		return err
+	}
+
+	exported := NewExportedSpec(spec, toc, opts.GetBinary(), opts.ShowHidden)
+	exported.KubernetesVersion = opts.KubernetesVersion
+
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(exported)
+	case "yaml":
+		bytes, err := yaml.Marshal(exported)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(bytes)
+		return err
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+}
+
+// NewExportedSpec merges the spec with the overrides of the ToC. The hidden commands and options
+// are excepted, unless showHidden is true
+func NewExportedSpec(spec *KubectlSpec, toc *ToC, binary string, showHidden bool) *ExportedSpec {
+	result := &ExportedSpec{
+		SchemaVersion:  ExportSchemaVersion,
+		KubectlVersion: spec.KubectlVersion,
+	}
+	for _, name := range spec.GetAllCommandNames() {
+		command := spec.GetCommand(name)
+		if command.Hidden && !showHidden {
+			continue
+		}
+		exported := ExportedCommand{
+			Name:        name,
+			Command:     command.GetCommandLine(binary),
+			Synopsis:    command.Synopsis,
+			Description: command.Description,
+			Usage:       command.Usage,
+		}
+		for _, example := range command.Examples {
+			exported.Examples = append(exported.Examples, ExportedExample{
+				Title:   example.Title,
+				Content: example.Content,
+			})
+		}
+
+		category, tocCommand := toc.GetCommand(name)
+		if category != nil {
+			exported.Category = category.Name
+		}
+		if tocCommand != nil {
+			if len(tocCommand.Usage) > 0 {
+				exported.Usage = tocCommand.Usage
+			}
+			for _, arg := range tocCommand.Args {
+				exportedArg := ExportedArg{
+					Name:   arg.Name,
+					Choice: "plain",
+					Rep:    "norepeat",
+					End:    arg.End,
+				}
+				if arg.Choice != nil {
+					exportedArg.Choice = *arg.Choice
+				}
+				if arg.Rep != nil {
+					exportedArg.Rep = *arg.Rep
+				}
+				exported.Args = append(exported.Args, exportedArg)
+			}
+		}
+
+		for _, option := range command.Options.visible(showHidden) {
+			exported.Options = append(exported.Options, newExportedOption(option, tocCommand, false))
+		}
+		for _, option := range command.InheritedOptions.visible(showHidden) {
+			exported.Options = append(exported.Options, newExportedOption(option, tocCommand, true))
+		}
+		result.Commands = append(result.Commands, exported)
+	}
+	return result
+}
+
+func newExportedOption(option *Option, tocCommand *ToCCommand, inherited bool) ExportedOption {
+	o := *option
+	var group *OptionsGroup
+	var tocOption *ToCOption
+	if tocCommand != nil {
+		group, tocOption = tocCommand.GetOption(option.Name)
+	}
+	if tocOption != nil {
+		o = option.WithToC(tocOption)
+	}
+	result := ExportedOption{
+		Name:      o.Name,
+		Shorthand: o.Shorthand,
+		Type:      o.Type,
+		Default:   o.DefaultValue,
+		Usage:     o.Usage,
+		Inherited: inherited,
+		InToC:     tocOption != nil,
+	}
+	if tocOption != nil {
+		result.Required = tocOption.Required
+		result.Group = group.Name
+	}
+	return result
+}
@@ -18,120 +18,311 @@ limitations under the License.
 package generators
 
 import (
-	"bufio"
-	"flag"
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 
-	"gopkg.in/yaml.v2"
+	"github.com/feloy/kubectl-reference/lang"
+	"github.com/feloy/kubectl-reference/static"
 )
 
-var KubernetesVersion = flag.String("kubernetes-version", "", "Version of Kubernetes to generate docs for.")
+// GenerateOptions holds the parameters of a generation, as given on the command line
+type GenerateOptions struct {
+	// KubernetesVersion is the version of Kubernetes to generate docs for (e.g. v1_31)
+	KubernetesVersion string
+	// GenKubectlDir is the directory containing the kubectl files of each version
+	GenKubectlDir string
+	// ShowUsage shows the original usage (for debugging)
+	ShowUsage bool
+	// ChangelogFrom is a toc.yaml or spec file of a previous version. If defined,
+	// the changes since this version are added as an appendix
+	ChangelogFrom string
+	// LiveSpec extracts the spec from the kubectl compiled in, even if a spec.yaml snapshot exists
+	LiveSpec bool
+	// Product is the binary of the registered product to document, kubectl by default
+	Product string
+	// ShowHidden documents the hidden commands and options
+	ShowHidden bool
+	// SkipDeprecated omits the deprecated commands
+	SkipDeprecated bool
+	// OptionsMatrix adds an appendix listing the commands accepting each option
+	OptionsMatrix bool
+	// MatrixOptions limits the options matrix to these options
+	MatrixOptions []string
+	// HelpBinary is an executable whose --help output is parsed to get the spec, instead of
+	// the registered product (e.g. a kubectl plugin)
+	HelpBinary string
+	// HelpDir is a directory containing the captured help texts parsed to get the spec,
+	// instead of the registered product
+	HelpDir string
+	// Lang is the language of the reference (e.g. fr or ja), English by default
+	Lang string
+}
 
-var GenKubectlDir = flag.String("gen-kubectl-dir", "generators", "Directory containing kubectl files")
+// GetBinary returns the binary of the product, prefixing the commands
+func (o *GenerateOptions) GetBinary() string {
+	if len(o.Product) == 0 {
+		return DefaultProduct
+	}
+	return o.Product
+}
 
-var ShowUsage = flag.Bool("show-usage", false, "Show original usage (for debugging)")
+// GetProduct returns the product to document: a product reading the help texts
+// if HelpBinary or HelpDir is set, the registered product otherwise
+func (o *GenerateOptions) GetProduct() (*Product, error) {
+	switch {
+	case len(o.HelpBinary) > 0:
+		return &Product{Name: o.GetBinary(), Binary: o.GetBinary(), Help: ExecHelp(o.HelpBinary)}, nil
+	case len(o.HelpDir) > 0:
+		prefix := strings.ReplaceAll(o.GetBinary(), " ", "_")
+		return &Product{Name: o.GetBinary(), Binary: o.GetBinary(), Help: DirHelp(o.HelpDir, prefix)}, nil
+	}
+	return GetProduct(o.Product)
+}
 
-func getTocFile() string {
-	return filepath.Join(*GenKubectlDir, *KubernetesVersion, "toc.yaml")
+// T returns the text of the book translated in the language of the reference
+func (o *GenerateOptions) T(text string) string {
+	return lang.T(o.Lang, text)
 }
 
-func getStaticIncludesDir() string {
-	return filepath.Join(*GenKubectlDir, *KubernetesVersion, "static_includes")
+func (o *GenerateOptions) GetTocFile() string {
+	return filepath.Join(o.GenKubectlDir, o.KubernetesVersion, "toc.yaml")
 }
 
-func AsDocbook() {
+func (o *GenerateOptions) GetManifestFile() string {
+	return filepath.Join(o.GenKubectlDir, o.KubernetesVersion, "manifest.yaml")
+}
 
-	f, err := os.Create("build/index.xml")
-	if err != nil {
-		panic(err)
+// GetSpecFile returns the spec.yaml snapshot of the version, or spec_<lang>.yaml
+// for a language other than English (e.g. spec_fr_FR.yaml)
+func (o *GenerateOptions) GetSpecFile() string {
+	if len(o.Lang) > 0 {
+		return filepath.Join(o.GenKubectlDir, o.KubernetesVersion, "spec_"+lang.Get(o.Lang)+".yaml")
 	}
-	defer f.Close()
-
-	fmt.Fprintf(f, `<?xml version="1.0" encoding="UTF-8"?>
-<!DOCTYPE book PUBLIC "-//OASIS//DTD DocBook XML V4.5//EN"
-"http://www.oasis-open.org/docbook/xml/4.5/docbookx.dtd">
-<book>
-  <bookinfo>
-    <title>Kubectl Reference</title>
+	return filepath.Join(o.GenKubectlDir, o.KubernetesVersion, "spec.yaml")
+}
 
-    <subtitle>v1.19</subtitle>
+func (o *GenerateOptions) GetStaticIncludesDir() string {
+	return filepath.Join(o.GenKubectlDir, o.KubernetesVersion, "static_includes")
+}
 
-    <releaseinfo>By the Kubernetes Authors</releaseinfo>
+// CheckLang returns an error if the language is not supported, or if the help texts
+// of the product compiled in are not translated in this language
+func (o *GenerateOptions) CheckLang(product *Product) error {
+	if len(o.Lang) == 0 {
+		return nil
+	}
+	if product.NewCommand == nil {
+		// the help texts read from a CLI are not translated, only the texts of the book
+		return lang.Check(o.Lang, false)
+	}
+	// the help texts of the commands compiled in are translated when the tool starts
+	return lang.Check(o.Lang, true)
+}
 
-    <releaseinfo>Edited and published by Philippe Martin</releaseinfo>
+// LoadSpec reads the spec of the Kubernetes version given in opts from its spec.yaml snapshot,
+// or gets it from the product if there is no snapshot, or opts.LiveSpec is set, or the spec is
+// read from the help of a CLI (opts.HelpBinary or opts.HelpDir is set)
+func LoadSpec(opts *GenerateOptions) (*KubectlSpec, error) {
+	product, err := opts.GetProduct()
+	if err != nil {
+		return nil, err
+	}
+	if len(opts.Lang) > 0 {
+		if err := lang.Check(opts.Lang, false); err != nil {
+			return nil, err
+		}
+	}
+	if !opts.LiveSpec && len(opts.HelpBinary) == 0 && len(opts.HelpDir) == 0 {
+		_, err := os.Stat(opts.GetSpecFile())
+		if err == nil {
+			spec, err := ReadSpec(opts.GetSpecFile())
+			if err != nil {
+				return nil, err
+			}
+			if len(spec.Product) > 0 && spec.Product != product.Binary {
+				return nil, fmt.Errorf("the spec file %s documents %s, not %s", opts.GetSpecFile(), spec.Product, product.Binary)
+			}
+			return spec, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	if err := opts.CheckLang(product); err != nil {
+		return nil, err
+	}
+	spec, err := product.GetSpec()
+	if err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
 
-    <copyright>
-      <year>2020</year>
+// load reads the ToC, the manifest and the spec of the Kubernetes version given in opts
+func load(opts *GenerateOptions) (*ToC, *Manifest, *KubectlSpec, error) {
+	if len(opts.KubernetesVersion) < 1 {
+		return nil, nil, nil, fmt.Errorf("must specify --kubernetes-version")
+	}
 
-      <holder>The Kubernetes Authors</holder>
-    </copyright>
+	toc, err := ReadToC(opts.GetTocFile())
+	if err != nil {
+		return nil, nil, nil, err
+	}
 
-    <legalnotice>
-      <para>Permission is granted to copy, distribute and/or modify this
-      document under the terms of the Apache License version 2. A copy of the
-      license is included in <xref linkend="license"/>.</para>
-    </legalnotice>
+	manifest, err := ReadManifest(opts.GetManifestFile())
+	if err != nil {
+		return nil, nil, nil, err
+	}
 
-    <legalnotice>
-      <para>The tool used to generate this document is available at
-      https://github.com/feloy/kubectl-reference</para>
-    </legalnotice>
-  </bookinfo>
-`)
+	spec, err := LoadSpec(opts)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 
-	spec := GetSpec()
+	toc.inlineGlobalOptions(opts.T("Global options"), spec)
+	toc.removeCommands(func(tocCommand *ToCCommand) bool {
+		command := spec.GetCommand(tocCommand.Name)
+		return command != nil && ((command.Hidden && !opts.ShowHidden) || (len(command.Deprecated) > 0 && opts.SkipDeprecated))
+	})
+	if !opts.ShowHidden {
+		toc.removeHiddenOptions(spec)
+	}
 
-	toc := ToC{}
-	if len(getTocFile()) < 1 {
-		fmt.Printf("Must specify --toc-file.\n")
-		os.Exit(2)
+	if len(manifest.Title) == 0 {
+		product, err := opts.GetProduct()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		manifest.Title = fmt.Sprintf(opts.T("%s Reference"), product.Name)
 	}
+	if len(manifest.Subtitle) == 0 {
+		manifest.Subtitle = spec.KubectlVersion
+	}
+	return toc, manifest, spec, nil
+}
 
-	contents, err := ioutil.ReadFile(getTocFile())
+// Generate writes the DocBook reference of the Kubernetes version given in opts to w.
+// The commands which cannot be rendered are omitted from the reference, and their errors
+// are returned, joined. A CommandNotFoundError or OptionNotFoundError can be found with errors.As
+func Generate(w io.Writer, opts *GenerateOptions) error {
+	toc, manifest, spec, err := load(opts)
 	if err != nil {
-		fmt.Printf("Failed to read yaml file %s: %v", getTocFile(), err)
+		return err
 	}
 
-	err = yaml.Unmarshal(contents, &toc)
-	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+	fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE book PUBLIC "-//OASIS//DTD DocBook XML V4.5//EN"
+"http://www.oasis-open.org/docbook/xml/4.5/docbookx.dtd">
+`)
+	if len(opts.Lang) > 0 {
+		// the XSL stylesheets translate the generated texts (Table of Contents, Index, ...)
+		fmt.Fprintf(w, "<book lang=\"%s\">\n", lang.Tag(opts.Lang))
+	} else {
+		fmt.Fprint(w, "<book>\n")
 	}
+	manifest.AsDocbook(w, opts)
 
+	// the commands which cannot be rendered are skipped, and the errors returned at the end
+	commands, errs := renderCommands(toc, spec, opts)
 	for _, category := range toc.Categories {
-		fmt.Fprintf(f, "  <reference><title>%s</title>\n", category.Name)
+		fmt.Fprintf(w, "  <reference id=\"%s\"><title>%s</title>\n", category.GetID(), category.Name)
 
-		for _, tocCommand := range category.Commands {
-			command := spec.GetCommand(tocCommand.Name)
-			if command == nil {
-				fmt.Printf("command %s not found", tocCommand.Name)
-				os.Exit(1)
+		if len(category.Include) > 0 {
+			if err := category.includeAsDocbook(w, opts); err != nil {
+				errs = append(errs, err)
 			}
-			command.AsDocbook(f, tocCommand)
 		}
-		fmt.Fprintf(f, `</reference>`)
+
+		for _, tocCommand := range category.Commands {
+			commands[tocCommand].WriteTo(w)
+		}
+		fmt.Fprintf(w, `</reference>`)
 	}
 
-	addLicense(f)
+	spec.GlobalOptionsAsDocbook(w, opts)
 
-	fmt.Fprintf(f, `</book>`)
+	if opts.OptionsMatrix {
+		NewOptionsMatrix(spec, opts.MatrixOptions, opts.ShowHidden).AsDocbook(w, toc, opts)
+	}
+
+	if len(opts.ChangelogFrom) > 0 {
+		from, err := readSnapshot(opts.ChangelogFrom)
+		if err != nil {
+			return err
+		}
+		to := newSnapshotFromSpec(spec, toc)
+		newChangelog(versionLabel(opts.ChangelogFrom), opts.KubernetesVersion, from, to).AsDocbook(w, opts)
+	}
+
+	if err := addLicense(w); err != nil {
+		return err
+	}
+
+	fmt.Fprint(w, "  <index/>\n")
+	fmt.Fprintf(w, `</book>`)
+	return errors.Join(errs...)
 }
 
-func addLicense(w io.Writer) {
-	f, err := os.Open("./static/license.xml")
-	if err != nil {
-		panic(err)
+// renderCommands renders the commands of the ToC as DocBook refentries. The commands which cannot
+// be rendered are removed from the ToC, and their errors returned, then the others are rendered
+// again, so their links only point to commands part of the book
+func renderCommands(toc *ToC, spec *KubectlSpec, opts *GenerateOptions) (map[*ToCCommand]*bytes.Buffer, []error) {
+	var errs []error
+	for {
+		rendered := map[*ToCCommand]*bytes.Buffer{}
+		failed := map[*ToCCommand]bool{}
+		for _, category := range toc.Categories {
+			for _, tocCommand := range category.Commands {
+				command := spec.GetCommand(tocCommand.Name)
+				if command == nil {
+					errs = append(errs, &CommandNotFoundError{Command: tocCommand.Name})
+					failed[tocCommand] = true
+					continue
+				}
+				var buf bytes.Buffer
+				if err := command.AsDocbook(&buf, toc, tocCommand, opts); err != nil {
+					errs = append(errs, err)
+					failed[tocCommand] = true
+					continue
+				}
+				rendered[tocCommand] = &buf
+			}
+		}
+		if len(failed) == 0 {
+			return rendered, errs
+		}
+		toc.removeCommands(func(tocCommand *ToCCommand) bool {
+			return failed[tocCommand]
+		})
 	}
-	defer f.Close()
+}
 
-	scanner := bufio.NewScanner(f)
+// includeAsDocbook writes the Markdown file included by the category as the partintro of the reference
+func (o *Category) includeAsDocbook(w io.Writer, opts *GenerateOptions) error {
+	filename := filepath.Join(opts.GetStaticIncludesDir(), o.Include)
+	contents, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("failed to read include file %s of category %s: %v", filename, o.Name, err)
+	}
+	if len(strings.TrimSpace(string(contents))) == 0 {
+		return nil
+	}
+	fmt.Fprint(w, "    <partintro>\n")
+	markdownToDocbook(w, string(contents), "      ")
+	fmt.Fprint(w, "    </partintro>\n")
+	return nil
+}
 
-	for scanner.Scan() {
-		w.Write(scanner.Bytes())
-		w.Write([]byte("\n"))
+func addLicense(w io.Writer) error {
+	if _, err := w.Write(static.License); err != nil {
+		return err
 	}
+	_, err := w.Write([]byte("\n"))
+	return err
 }
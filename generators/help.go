@@ -0,0 +1,349 @@
+/*
+Copyright 2019 Philippe Martin.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// HelpFunc returns the help text of the command with the given path
+// (e.g. [create deployment]), or of the root command if path is empty
+type HelpFunc func(path []string) (string, error)
+
+// ExecHelp returns a HelpFunc executing the binary with the --help flag
+func ExecHelp(binary string) HelpFunc {
+	return func(path []string) (string, error) {
+		out, err := exec.Command(binary, append(path, "--help")...).Output()
+		if err != nil && len(out) == 0 {
+			return "", fmt.Errorf("failed to get the help of %s %s: %v", binary, strings.Join(path, " "), err)
+		}
+		return string(out), nil
+	}
+}
+
+// DirHelp returns a HelpFunc reading captured help texts from dir. The help of a command
+// is read from the file named after the prefix and the path of the command (e.g. kubectl_create_deployment.txt),
+// the help of the root command from the file named after the prefix (e.g. kubectl.txt)
+func DirHelp(dir string, prefix string) HelpFunc {
+	return func(path []string) (string, error) {
+		filename := filepath.Join(dir, strings.Join(append([]string{prefix}, path...), "_")+".txt")
+		contents, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return "", fmt.Errorf("failed to read help file %s: %v", filename, err)
+		}
+		return string(contents), nil
+	}
+}
+
+// helpSections are the sections of a cobra or kubectl help, in addition to the groups of commands
+var helpSections = map[string]struct{}{
+	"Usage":                  {},
+	"Aliases":                {},
+	"Examples":               {},
+	"Flags":                  {},
+	"Global Flags":           {},
+	"Options":                {},
+	"Additional help topics": {},
+}
+
+// help is the content of the help of a command
+type help struct {
+	description string
+	usage       string
+	aliases     []string
+	examples    []string
+	commands    []helpEntry
+	options     Options
+	inherited   Options
+}
+
+// helpEntry is a subcommand listed in the help of its parent
+type helpEntry struct {
+	name     string
+	synopsis string
+}
+
+// pflag format: "  -m, --mode string   The mode (default "fast")"
+var helpPflagRegexp = regexp.MustCompile(`^\s+(?:-(\S), )?--([^\s=]+)(?: (\S+))?\s{2,}(.*)$`)
+
+// kubectl format: "    -o, --output='json':" followed by the usage on the next lines
+var helpKubectlFlagRegexp = regexp.MustCompile(`^\s+(?:-(\S), )?--([^\s=]+)=(.*):$`)
+
+var helpDefaultRegexp = regexp.MustCompile(`\s*\(default (.*)\)$`)
+
+var helpEntryRegexp = regexp.MustCompile(`^\s+(\S+)\s+(.*)$`)
+
+// pflagTypes maps the names of the values displayed by pflag to the types of the flags
+var pflagTypes = map[string]string{
+	"":               "bool",
+	"string":         "string",
+	"strings":        "stringSlice",
+	"stringArray":    "stringArray",
+	"stringToString": "stringToString",
+	"int":            "int",
+	"int32":          "int32",
+	"int64":          "int64",
+	"ints":           "intSlice",
+	"uint":           "uint",
+	"float32":        "float32",
+	"float64":        "float64",
+	"duration":       "duration",
+}
+
+// NewSpecFromHelp returns the spec of a cobra-based CLI, by parsing the help of its commands
+func NewSpecFromHelp(helpFunc HelpFunc) (KubectlSpec, error) {
+	root, err := readHelp(helpFunc, nil)
+	if err != nil {
+		return KubectlSpec{}, err
+	}
+
+	tlc := TopLevelCommands{}
+	for _, entry := range root.commands {
+		h, err := readHelp(helpFunc, []string{entry.name})
+		if err != nil {
+			return KubectlSpec{}, err
+		}
+		result := TopLevelCommand{
+			MainCommand: h.asCommand(entry, "", nil),
+		}
+		for _, sub := range h.commands {
+			subCommands, err := newSubCommandsFromHelp(helpFunc, []string{entry.name}, sub, h.commands)
+			if err != nil {
+				return KubectlSpec{}, err
+			}
+			result.SubCommands = append(result.SubCommands, subCommands...)
+		}
+		sort.Sort(result.SubCommands)
+		tlc.Commands = append(tlc.Commands, result)
+	}
+	sort.Sort(tlc)
+
+	return KubectlSpec{
+		TopLevelCommandGroups: []TopLevelCommands{tlc},
+	}, nil
+}
+
+func newSubCommandsFromHelp(helpFunc HelpFunc, parent []string, entry helpEntry, siblings []helpEntry) (Commands, error) {
+	path := append(append([]string{}, parent...), entry.name)
+	h, err := readHelp(helpFunc, path)
+	if err != nil {
+		return nil, err
+	}
+	subCommands := Commands{h.asCommand(entry, strings.Join(parent, "/"), siblings)}
+	for _, sub := range h.commands {
+		subs, err := newSubCommandsFromHelp(helpFunc, path, sub, h.commands)
+		if err != nil {
+			return nil, err
+		}
+		subCommands = append(subCommands, subs...)
+	}
+	return subCommands, nil
+}
+
+func readHelp(helpFunc HelpFunc, path []string) (*help, error) {
+	text, err := helpFunc(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseHelp(text), nil
+}
+
+// asCommand returns the command described by the help, listed as entry in the help of its parent
+// with its siblings
+func (o *help) asCommand(entry helpEntry, path string, siblings []helpEntry) *Command {
+	var aliases, childNames, siblingNames []string
+	for _, alias := range o.aliases {
+		// the name of the command is listed first
+		if alias != entry.name {
+			aliases = append(aliases, alias)
+		}
+	}
+	for _, child := range o.commands {
+		childNames = append(childNames, child.name)
+	}
+	for _, sibling := range siblings {
+		if sibling.name != entry.name {
+			siblingNames = append(siblingNames, sibling.name)
+		}
+	}
+	return &Command{
+		Name:             entry.name,
+		Path:             path,
+		Synopsis:         entry.synopsis,
+		Description:      o.description,
+		Examples:         SplitExamples(strings.Join(o.examples, "\n")),
+		Options:          o.options,
+		InheritedOptions: o.inherited,
+		SeeAlso:          newSeeAlso(path, entry.name, childNames, siblingNames),
+		Usage:            o.usageOf(entry.name),
+		Aliases:          aliases,
+	}
+}
+
+// usageOf returns the usage of the command as defined in cobra (e.g. "deployment NAME --image=image"),
+// from the usage line of the help (e.g. "kubectl create deployment NAME --image=image [options]")
+func (o *help) usageOf(name string) string {
+	usage := strings.TrimSuffix(strings.TrimSuffix(o.usage, " [flags]"), " [options]")
+	if i := strings.Index(usage, " "+name); i >= 0 {
+		return usage[i+1:]
+	}
+	return usage
+}
+
+// isHelpSection returns true if the line is the title of a section of the help
+func isHelpSection(line string) bool {
+	if !strings.HasSuffix(line, ":") || strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+		return false
+	}
+	name := strings.TrimSuffix(line, ":")
+	if _, found := helpSections[name]; found {
+		return true
+	}
+	return strings.Contains(name, "Commands")
+}
+
+func parseHelp(text string) *help {
+	result := &help{}
+	var description, flags, globalFlags []string
+	section := ""
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if isHelpSection(line) {
+			section = strings.TrimSuffix(line, ":")
+			continue
+		}
+		switch {
+		case section == "":
+			description = append(description, line)
+		case section == "Usage":
+			if trimmed := strings.TrimSpace(line); len(result.usage) == 0 && len(trimmed) > 0 && line != trimmed {
+				result.usage = trimmed
+			}
+		case section == "Aliases":
+			for _, alias := range strings.Split(line, ",") {
+				if alias = strings.TrimSpace(alias); len(alias) > 0 {
+					result.aliases = append(result.aliases, alias)
+				}
+			}
+		case section == "Examples":
+			result.examples = append(result.examples, line)
+		case section == "Flags" || section == "Options":
+			flags = append(flags, line)
+		case section == "Global Flags":
+			globalFlags = append(globalFlags, line)
+		case strings.Contains(section, "Commands"):
+			if matches := helpEntryRegexp.FindStringSubmatch(line); matches != nil && matches[1] != "help" {
+				result.commands = append(result.commands, helpEntry{
+					name:     matches[1],
+					synopsis: strings.TrimSpace(matches[2]),
+				})
+			}
+		}
+	}
+	result.description = strings.TrimSpace(strings.Join(description, "\n"))
+	result.options = parseHelpFlags(flags)
+	result.inherited = parseHelpFlags(globalFlags)
+	return result
+}
+
+// parseHelpFlags parses the flags listed in a help, in the pflag or kubectl format.
+// The help flag is skipped
+func parseHelpFlags(lines []string) Options {
+	result := Options{}
+	var current *Option
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if matches := helpKubectlFlagRegexp.FindStringSubmatch(line); matches != nil {
+			current = &Option{
+				Name:         matches[2],
+				Shorthand:    matches[1],
+				DefaultValue: strings.Trim(matches[3], "'"),
+				Type:         guessType(matches[3]),
+			}
+			result = append(result, current)
+			continue
+		}
+		if matches := helpPflagRegexp.FindStringSubmatch(line); matches != nil {
+			current = &Option{
+				Name:      matches[2],
+				Shorthand: matches[1],
+				Usage:     matches[4],
+				Type:      pflagType(matches[3]),
+			}
+			if def := helpDefaultRegexp.FindStringSubmatch(current.Usage); def != nil {
+				current.DefaultValue = strings.Trim(def[1], `"`)
+				current.Usage = helpDefaultRegexp.ReplaceAllString(current.Usage, "")
+			} else if current.Type == "bool" {
+				current.DefaultValue = "false"
+			}
+			result = append(result, current)
+			continue
+		}
+		if current != nil && len(trimmed) > 0 {
+			if len(current.Usage) > 0 {
+				current.Usage += "\n"
+			}
+			current.Usage += trimmed
+		}
+	}
+
+	options := Options{}
+	for _, option := range result {
+		if option.Name != "help" {
+			options = append(options, option)
+		}
+	}
+	sort.Sort(options)
+	return options
+}
+
+// pflagType returns the type of a flag from the name of its value displayed by pflag.
+// The unknown names are custom names given in the usage, for string values
+func pflagType(name string) string {
+	if t, found := pflagTypes[name]; found {
+		return t
+	}
+	return "string"
+}
+
+// durationRegexp matches the durations as formatted by Go (e.g. 0s, 1m30s)
+var durationRegexp = regexp.MustCompile(`^(\d+h)?(\d+m)?(\d+(\.\d+)?(s|ms|µs|ns))$`)
+
+// integerRegexp matches the integers as displayed by kubectl (e.g. -1, 0)
+var integerRegexp = regexp.MustCompile(`^-?\d+$`)
+
+// guessType returns the type of a flag from its default value, as displayed by kubectl
+func guessType(def string) string {
+	switch {
+	case strings.HasPrefix(def, "'"):
+		return "string"
+	case def == "true" || def == "false":
+		return "bool"
+	case strings.HasPrefix(def, "["):
+		return "stringSlice"
+	case durationRegexp.MatchString(def):
+		return "duration"
+	case integerRegexp.MatchString(def):
+		return "int"
+	}
+	return "string"
+}
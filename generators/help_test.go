@@ -0,0 +1,309 @@
+/*
+Copyright 2019 Philippe Martin.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func readTestHelp(t *testing.T, name string) string {
+	t.Helper()
+	contents, err := os.ReadFile(filepath.Join("testdata", "help", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(contents)
+}
+
+func TestParseHelp(t *testing.T) {
+	tests := []struct {
+		name        string
+		file        string
+		description string
+		usage       string
+		aliases     []string
+		commands    []helpEntry
+		options     []string
+		inherited   []string
+		examples    int
+	}{
+		{
+			name:        "cobra root",
+			file:        "cobra/kubectl_foo.txt",
+			description: "Foo manages the foos of the cluster.",
+			usage:       "kubectl foo [command]",
+			commands:    []helpEntry{{name: "bar", synopsis: "Print the bars"}},
+			options:     []string{"namespace", "verbose"},
+		},
+		{
+			name:        "cobra command",
+			file:        "cobra/kubectl_foo_bar.txt",
+			description: "Print the bars of the namespace, with their sizes.",
+			usage:       "kubectl foo bar [NAME] [flags]",
+			aliases:     []string{"bar", "bars", "b"},
+			commands:    []helpEntry{{name: "count", synopsis: "Count the bars"}},
+			options:     []string{"limit", "output", "selector", "timeout"},
+			inherited:   []string{"namespace", "verbose"},
+			examples:    6,
+		},
+		{
+			name:        "kubectl root",
+			file:        "kubectl/kubectl.txt",
+			description: "kubectl controls the Kubernetes cluster manager.",
+			usage:       "kubectl [flags] [options]",
+			commands: []helpEntry{
+				{name: "create", synopsis: "Create a resource from a file or from stdin"},
+				{name: "rollout", synopsis: "Manage the rollout of a resource"},
+			},
+		},
+		{
+			name:        "kubectl command",
+			file:        "kubectl/kubectl_rollout_status.txt",
+			description: "Show the status of the rollout.\n\n By default 'rollout status' will watch the status of the latest rollout until it's done. If you don't want to wait for the rollout to finish then you can use --watch=false. Note that if a new rollout starts in-between, then 'rollout status' will continue watching the latest revision. If you want to pin to a specific revision and abort if it is rolled over by another revision, use --revision=N where N is the revision you need to watch for.",
+			usage:       "kubectl rollout status (TYPE NAME | TYPE/NAME) [flags] [options]",
+			options:     []string{"filename", "kustomize", "recursive", "revision", "selector", "timeout", "watch"},
+			examples:    3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := parseHelp(readTestHelp(t, tt.file))
+			if h.description != tt.description {
+				t.Errorf("description: got %q, want %q", h.description, tt.description)
+			}
+			if h.usage != tt.usage {
+				t.Errorf("usage: got %q, want %q", h.usage, tt.usage)
+			}
+			if !reflect.DeepEqual(h.aliases, tt.aliases) {
+				t.Errorf("aliases: got %v, want %v", h.aliases, tt.aliases)
+			}
+			if !reflect.DeepEqual(h.commands, tt.commands) {
+				t.Errorf("commands: got %v, want %v", h.commands, tt.commands)
+			}
+			if got := optionNames(h.options); !reflect.DeepEqual(got, tt.options) {
+				t.Errorf("options: got %v, want %v", got, tt.options)
+			}
+			if got := optionNames(h.inherited); !reflect.DeepEqual(got, tt.inherited) {
+				t.Errorf("inherited options: got %v, want %v", got, tt.inherited)
+			}
+			if len(h.examples) != tt.examples {
+				t.Errorf("examples: got %d lines, want %d", len(h.examples), tt.examples)
+			}
+		})
+	}
+}
+
+func TestParseHelpFlags(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  Options
+	}{
+		{
+			name: "pflag string with default",
+			lines: []string{
+				`  -o, --output string      Output format (default "yaml")`,
+			},
+			want: Options{{Name: "output", Shorthand: "o", Type: "string", DefaultValue: "yaml", Usage: "Output format"}},
+		},
+		{
+			name: "pflag bool",
+			lines: []string{
+				`  -v, --verbose   Verbose output`,
+			},
+			want: Options{{Name: "verbose", Shorthand: "v", Type: "bool", DefaultValue: "false", Usage: "Verbose output"}},
+		},
+		{
+			name: "pflag typed values, sorted, help skipped",
+			lines: []string{
+				`  -h, --help               help for bar`,
+				`      --timeout duration   Timeout of the request (default 30s)`,
+				`  -l, --selector strings   Selectors of the bars`,
+				`      --limit int          Maximum number of bars (default 10)`,
+			},
+			want: Options{
+				{Name: "limit", Type: "int", DefaultValue: "10", Usage: "Maximum number of bars"},
+				{Name: "selector", Shorthand: "l", Type: "stringSlice", Usage: "Selectors of the bars"},
+				{Name: "timeout", Type: "duration", DefaultValue: "30s", Usage: "Timeout of the request"},
+			},
+		},
+		{
+			name: "pflag custom value name",
+			lines: []string{
+				`      --mode MODE   The mode`,
+			},
+			want: Options{{Name: "mode", Type: "string", Usage: "The mode"}},
+		},
+		{
+			name: "pflag usage continued on the next line",
+			lines: []string{
+				`      --all    Count the bars`,
+				`               of all the namespaces`,
+			},
+			want: Options{{Name: "all", Type: "bool", DefaultValue: "false", Usage: "Count the bars\nof all the namespaces"}},
+		},
+		{
+			name: "kubectl format",
+			lines: []string{
+				`    -o, --output='':`,
+				"\tOutput format.",
+				``,
+				`    --replicas=1:`,
+				"\tNumber of replicas to create.",
+				"\tDefault is 1.",
+				``,
+				`    --image=[]:`,
+				"\tImage names to run.",
+			},
+			want: Options{
+				{Name: "image", Type: "stringSlice", DefaultValue: "[]", Usage: "Image names to run."},
+				{Name: "output", Shorthand: "o", Type: "string", Usage: "Output format."},
+				{Name: "replicas", Type: "int", DefaultValue: "1", Usage: "Number of replicas to create.\nDefault is 1."},
+			},
+		},
+		{
+			name:  "no flags",
+			lines: []string{"", "Use \"kubectl options\" for a list of global command-line options."},
+			want:  Options{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseHelpFlags(tt.lines)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", formatOptions(got), formatOptions(tt.want))
+			}
+		})
+	}
+}
+
+func formatOptions(options Options) (result []Option) {
+	for _, option := range options {
+		result = append(result, *option)
+	}
+	return
+}
+
+func TestGuessType(t *testing.T) {
+	tests := []struct {
+		def  string
+		want string
+	}{
+		{def: "''", want: "string"},
+		{def: "'kubectl-create'", want: "string"},
+		{def: "true", want: "bool"},
+		{def: "false", want: "bool"},
+		{def: "[]", want: "stringSlice"},
+		{def: "0s", want: "duration"},
+		{def: "1m30s", want: "duration"},
+		{def: "0", want: "int"},
+		{def: "-1", want: "int"},
+		{def: "1.5", want: "string"},
+		{def: "none", want: "string"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.def, func(t *testing.T) {
+			if got := guessType(tt.def); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewSpecFromHelp(t *testing.T) {
+	tests := []struct {
+		name     string
+		dir      string
+		prefix   string
+		commands []string
+		check    func(t *testing.T, spec *KubectlSpec)
+	}{
+		{
+			name:     "cobra",
+			dir:      "cobra",
+			prefix:   "kubectl_foo",
+			commands: []string{"bar", "bar/count"},
+			check: func(t *testing.T, spec *KubectlSpec) {
+				bar := spec.GetCommand("bar")
+				if bar.Synopsis != "Print the bars" {
+					t.Errorf("synopsis: got %q", bar.Synopsis)
+				}
+				if bar.Usage != "bar [NAME]" {
+					t.Errorf("usage: got %q", bar.Usage)
+				}
+				if !reflect.DeepEqual(bar.Aliases, []string{"bars", "b"}) {
+					t.Errorf("aliases: got %v", bar.Aliases)
+				}
+				if len(bar.Examples) != 2 || bar.Examples[1].Title != "Print the bar named baz as JSON" {
+					t.Errorf("examples: got %v", bar.Examples)
+				}
+				if got := optionNames(bar.InheritedOptions); !reflect.DeepEqual(got, []string{"namespace", "verbose"}) {
+					t.Errorf("inherited options: got %v", got)
+				}
+				if got := optionNames(spec.GetGlobalOptions(false)); !reflect.DeepEqual(got, []string{"namespace", "verbose"}) {
+					t.Errorf("global options: got %v", got)
+				}
+				if count := spec.GetCommand("bar/count"); count.Usage != "count" {
+					t.Errorf("usage of count: got %q", count.Usage)
+				}
+			},
+		},
+		{
+			name:     "kubectl",
+			dir:      "kubectl",
+			prefix:   "kubectl",
+			commands: []string{"create", "create/deployment", "rollout", "rollout/status"},
+			check: func(t *testing.T, spec *KubectlSpec) {
+				deployment := spec.GetCommand("create/deployment")
+				if deployment.Usage != "deployment NAME --image=image -- [COMMAND] [args...]" {
+					t.Errorf("usage: got %q", deployment.Usage)
+				}
+				if !reflect.DeepEqual(deployment.Aliases, []string{"deploy"}) {
+					t.Errorf("aliases: got %v", deployment.Aliases)
+				}
+				if option := deployment.GetOption("replicas"); option == nil || option.Type != "int" || option.Shorthand != "r" {
+					t.Errorf("replicas: got %v", option)
+				}
+				if option := spec.GetCommand("rollout/status").GetOption("timeout"); option == nil || option.Type != "duration" {
+					t.Errorf("timeout: got %v", option)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := NewSpecFromHelp(DirHelp(filepath.Join("testdata", "help", tt.dir), tt.prefix))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := spec.GetAllCommandNames(); !reflect.DeepEqual(got, tt.commands) {
+				t.Fatalf("commands: got %v, want %v", got, tt.commands)
+			}
+			tt.check(t, &spec)
+		})
+	}
+}
+
+func TestNewSpecFromHelpMissingFile(t *testing.T) {
+	_, err := NewSpecFromHelp(DirHelp(filepath.Join("testdata", "help", "cobra"), "kubectl_unknown"))
+	if err == nil {
+		t.Error("expected an error")
+	}
+}
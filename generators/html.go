@@ -0,0 +1,386 @@
+/*
+Copyright 2019 Philippe Martin.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"errors"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/feloy/kubectl-reference/lang"
+)
+
+// htmlBook is the model of the HTML templates
+type htmlBook struct {
+	// Lang is the language tag of the book (e.g. en, fr-FR)
+	Lang       string
+	Manifest   *Manifest
+	Categories []*htmlCategory
+	// SinglePage is true when all the commands are rendered in the same page
+	SinglePage bool
+	// Current is the command rendered in the page, for a multi-page book
+	Current *htmlCommand
+}
+
+type htmlCategory struct {
+	ID       string
+	Name     string
+	Commands []*htmlCommand
+}
+
+type htmlCommand struct {
+	ID            string
+	RefName       string
+	CommandLine   string
+	Page          string
+	Category      *htmlCategory
+	Synopsis      string
+	Usage         string
+	OriginalUsage string
+	Description   []string
+	OptionsGroups []htmlOptionsGroup
+	Examples      []Example
+	Previous      *htmlCommand
+	Next          *htmlCommand
+}
+
+type htmlOptionsGroup struct {
+	Name    string
+	Options []htmlOption
+}
+
+type htmlOption struct {
+	ID        string
+	Name      string
+	Shorthand string
+	Type      string
+	Default   string
+	Usage     string
+	// Deprecation describes the deprecation of the option, if any
+	Deprecation string
+}
+
+// GenerateHTML writes the reference of the Kubernetes version given in opts
+// as a single self-contained HTML page to w
+func GenerateHTML(w io.Writer, opts *GenerateOptions) error {
+	book, err := newHTMLBook(opts)
+	if book == nil {
+		return err
+	}
+	book.SinglePage = true
+	return errors.Join(err, newHTMLTemplates(opts).ExecuteTemplate(w, "page", book))
+}
+
+// GenerateHTMLPages writes the reference of the Kubernetes version given in opts into dir,
+// as an index page and one HTML page per command
+func GenerateHTMLPages(dir string, opts *GenerateOptions) error {
+	book, err := newHTMLBook(opts)
+	if book == nil {
+		return err
+	}
+	errs := []error{err}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	templates := newHTMLTemplates(opts)
+	if err := writeHTMLPage(filepath.Join(dir, "index.html"), templates, book); err != nil {
+		return err
+	}
+	for _, category := range book.Categories {
+		for _, command := range category.Commands {
+			page := *book
+			page.Current = command
+			if err := writeHTMLPage(filepath.Join(dir, command.Page), templates, &page); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func writeHTMLPage(filename string, templates *template.Template, book *htmlBook) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := templates.ExecuteTemplate(f, "page", book); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// newHTMLBook returns the model of the HTML book. When some commands cannot be rendered,
+// the book is returned without them, with the errors
+func newHTMLBook(opts *GenerateOptions) (*htmlBook, error) {
+	toc, manifest, spec, err := load(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	book := &htmlBook{
+		Lang:     "en",
+		Manifest: manifest,
+	}
+	if len(opts.Lang) > 0 {
+		book.Lang = lang.Tag(opts.Lang)
+	}
+	var previous *htmlCommand
+	var errs []error
+	for _, category := range toc.Categories {
+		htmlCat := &htmlCategory{
+			ID:   category.GetID(),
+			Name: category.Name,
+		}
+		for _, tocCommand := range category.Commands {
+			command := spec.GetCommand(tocCommand.Name)
+			if command == nil {
+				errs = append(errs, &CommandNotFoundError{Command: tocCommand.Name})
+				continue
+			}
+			htmlCmd, err := command.asHTML(tocCommand, opts)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			htmlCmd.Category = htmlCat
+			if previous != nil {
+				previous.Next = htmlCmd
+				htmlCmd.Previous = previous
+			}
+			previous = htmlCmd
+			htmlCat.Commands = append(htmlCat.Commands, htmlCmd)
+		}
+		book.Categories = append(book.Categories, htmlCat)
+	}
+	return book, errors.Join(errs...)
+}
+
+func (o *Command) asHTML(config *ToCCommand, opts *GenerateOptions) (*htmlCommand, error) {
+	refname := o.GetRefName()
+	commandLine := o.GetCommandLine(opts.GetBinary())
+	id := commandID(config.Name)
+	result := &htmlCommand{
+		ID:          id,
+		RefName:     refname,
+		CommandLine: commandLine,
+		Page:        strings.ReplaceAll(commandLine, " ", "_") + ".html",
+		Synopsis:    o.Synopsis,
+		Examples:    o.Examples,
+	}
+	if opts.ShowUsage {
+		result.OriginalUsage = o.Usage
+	}
+
+	for _, para := range strings.Split(o.Description, "\n\n") {
+		if para = strings.TrimSpace(para); len(para) > 0 {
+			result.Description = append(result.Description, para)
+		}
+	}
+
+	usage, err := o.TextSynopsis(opts.GetBinary(), config)
+	if err != nil {
+		return nil, err
+	}
+	result.Usage = usage
+
+	for _, group := range config.OptionsGroups {
+		if len(group.Options) == 0 {
+			continue
+		}
+		htmlGroup := htmlOptionsGroup{
+			Name: group.Name,
+		}
+		for _, tocOption := range group.Options {
+			option := o.FindOption(tocOption.Name)
+			if option == nil {
+				return nil, &OptionNotFoundError{Command: config.Name, Option: tocOption.Name}
+			}
+			opt := option.WithToC(&tocOption)
+			htmlOpt := htmlOption{
+				ID:          optionID(config.Name, opt.Name),
+				Name:        opt.Name,
+				Shorthand:   opt.Shorthand,
+				Type:        opt.Type,
+				Usage:       opt.Usage,
+				Deprecation: opt.GetDeprecationNote(opts),
+			}
+			if len(opt.DefaultValue) > 0 && opt.DefaultValue != "[]" {
+				htmlOpt.Default = opt.DefaultValue
+			}
+			htmlGroup.Options = append(htmlGroup.Options, htmlOpt)
+		}
+		result.OptionsGroups = append(result.OptionsGroups, htmlGroup)
+	}
+	return result, nil
+}
+
+// Link returns the link to the element of the command with the given id,
+// relative to the page being rendered
+func (o *htmlBook) Link(command *htmlCommand, id string) string {
+	if o.SinglePage {
+		return "#" + id
+	}
+	if id == command.ID {
+		return command.Page
+	}
+	return command.Page + "#" + id
+}
+
+// newHTMLTemplates returns the HTML templates, translating their texts in the language of opts
+func newHTMLTemplates(opts *GenerateOptions) *template.Template {
+	return template.Must(htmlTemplates.Clone()).Funcs(template.FuncMap{"T": opts.T})
+}
+
+// htmlTemplates are the HTML templates. The T function, translating a text, is replaced by newHTMLTemplates
+var htmlTemplates = template.Must(template.New("html").Funcs(template.FuncMap{"T": func(s string) string { return s }}).Parse(`
+{{- define "page" -}}
+<!DOCTYPE html>
+<html lang="{{ .Lang }}">
+<head>
+<meta charset="utf-8">
+<title>{{ if .Current }}{{ .Current.CommandLine }} - {{ end }}{{ .Manifest.Title }}</title>
+<style>
+body { margin: 0; font-family: sans-serif; line-height: 1.5; color: #222; }
+nav { position: fixed; top: 0; bottom: 0; left: 0; width: 18em; overflow-y: auto; padding: 1em; background: #f5f5f5; border-right: 1px solid #ddd; font-size: 0.9em; }
+nav h2 { font-size: 1em; margin: 1em 0 0.3em; }
+nav ul { list-style: none; margin: 0; padding: 0; }
+nav a { color: #326ce5; text-decoration: none; }
+nav a.current { font-weight: bold; }
+main { margin-left: 21em; padding: 1em 2em; max-width: 60em; }
+pre { background: #f5f5f5; padding: 0.8em; overflow-x: auto; }
+pre.example .prompt { color: #888; user-select: none; }
+pre.example .command, pre.example .continuation { font-weight: bold; }
+pre.example .output { color: #555; }
+tr.deprecated td.option { text-decoration: line-through; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; vertical-align: top; padding: 0.3em 0.6em; border-bottom: 1px solid #ddd; }
+td.option { white-space: nowrap; font-family: monospace; }
+a.anchor { color: #aaa; text-decoration: none; margin-left: 0.3em; }
+.navigation { display: flex; justify-content: space-between; margin-top: 2em; }
+</style>
+</head>
+<body>
+{{ template "sidebar" . }}
+<main>
+{{- if .SinglePage }}
+{{ template "title" . }}
+{{- range .Categories }}
+<section id="{{ .ID }}">
+<h1>{{ .Name }}</h1>
+{{- range .Commands }}
+{{ template "command" . }}
+{{- end }}
+</section>
+{{- end }}
+{{- else if .Current }}
+<p><a href="index.html">{{ .Manifest.Title }}</a> &rsaquo; <a href="index.html#{{ .Current.Category.ID }}">{{ .Current.Category.Name }}</a></p>
+{{ template "command" .Current }}
+<div class="navigation">
+<span>{{ with .Current.Previous }}&lsaquo; <a href="{{ .Page }}">{{ .CommandLine }}</a>{{ end }}</span>
+<span>{{ with .Current.Next }}<a href="{{ .Page }}">{{ .CommandLine }}</a> &rsaquo;{{ end }}</span>
+</div>
+{{- else }}
+{{ template "title" . }}
+{{- range .Categories }}
+<section id="{{ .ID }}">
+<h2>{{ .Name }}</h2>
+<ul>
+{{- range .Commands }}
+<li><a href="{{ .Page }}">{{ .CommandLine }}</a>: {{ .Synopsis }}</li>
+{{- end }}
+</ul>
+</section>
+{{- end }}
+{{- end }}
+</main>
+</body>
+</html>
+{{ end -}}
+
+{{- define "title" }}
+<h1>{{ .Manifest.Title }}</h1>
+{{- with .Manifest.Subtitle }}
+<p>{{ . }}</p>
+{{- end }}
+{{- end -}}
+
+{{- define "sidebar" }}
+<nav>
+<a href="{{ if .SinglePage }}#{{ else }}index.html{{ end }}">{{ .Manifest.Title }}</a>
+{{- $book := . }}
+{{- range .Categories }}
+<h2><a href="{{ if $book.SinglePage }}{{ else }}index.html{{ end }}#{{ .ID }}">{{ .Name }}</a></h2>
+<ul>
+{{- range .Commands }}
+<li><a href="{{ $book.Link . .ID }}"{{ if eq . $book.Current }} class="current"{{ end }}>{{ .RefName }}</a></li>
+{{- end }}
+</ul>
+{{- end }}
+</nav>
+{{- end -}}
+
+{{- define "command" }}
+<article id="{{ .ID }}">
+<h2>{{ .CommandLine }}<a class="anchor" href="#{{ .ID }}">#</a></h2>
+<p>{{ .Synopsis }}</p>
+<h3>{{ T "Usage" }}</h3>
+<pre>{{ .Usage }}</pre>
+{{- with .OriginalUsage }}
+<h3>{{ T "Original Usage" }}</h3>
+<pre>{{ . }}</pre>
+{{- end }}
+{{- with .Description }}
+<h3>{{ T "Description" }}</h3>
+{{- range . }}
+<p>{{ . }}</p>
+{{- end }}
+{{- end }}
+{{- with .OptionsGroups }}
+<h3>{{ T "Options" }}</h3>
+{{- range . }}
+{{- with .Name }}
+<h4>{{ . }}</h4>
+{{- end }}
+<table>
+<tr><th>{{ T "Option" }}</th><th>{{ T "Type" }}</th><th>{{ T "Default" }}</th><th>{{ T "Description" }}</th></tr>
+{{- range .Options }}
+<tr id="{{ .ID }}"{{ if .Deprecation }} class="deprecated"{{ end }}><td class="option">{{ with .Shorthand }}-{{ . }}, {{ end }}--{{ .Name }}<a class="anchor" href="#{{ .ID }}">#</a></td><td>{{ .Type }}</td><td>{{ .Default }}</td><td>{{ with .Deprecation }}<strong>{{ . }}.</strong> {{ end }}{{ .Usage }}</td></tr>
+{{- end }}
+</table>
+{{- end }}
+{{- end }}
+{{- with .Examples }}
+<h3>{{ T "Examples" }}</h3>
+{{- range . }}
+{{- with .Title }}
+<p>{{ . }}</p>
+{{- end }}
+<pre class="example">
+{{- range $i, $line := .GetLines }}{{ if $i }}{{ "\n" }}{{ end }}
+{{- with $line.Prompt }}<span class="prompt">{{ . }}</span>{{ end }}<span class="{{ $line.Kind }}">{{ $line.Text }}</span>
+{{- end }}</pre>
+{{- end }}
+{{- end }}
+</article>
+{{- end -}}
+`))
@@ -0,0 +1,74 @@
+/*
+Copyright 2019 Philippe Martin.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import "strings"
+
+// The ids of the elements of the reference are derived from the names of the categories,
+// commands and options only, so they stay stable across versions and can be targeted
+// by links from other documents. The same ids are used in the DocBook and HTML outputs:
+//
+//	category: cat-<category>              e.g. cat-basic-commands-beginner
+//	command:  cmd-<command>               e.g. cmd-create-deployment
+//	option:   cmd-<command>-opt-<option>  e.g. cmd-create-deployment-opt-replicas
+//	global option: global-opt-<option>    e.g. global-opt-namespace
+
+// categoryID returns the id of the category with the given name
+func categoryID(name string) string {
+	return "cat-" + toID(name)
+}
+
+// commandID returns the id of the command with the given name in the ToC (e.g. cmd-create-deployment for create/deployment)
+func commandID(name string) string {
+	return "cmd-" + toID(name)
+}
+
+// globalOptionsID is the id of the appendix of the global options
+const globalOptionsID = "global-options"
+
+// optionsMatrixID is the id of the appendix listing the commands accepting each option
+const optionsMatrixID = "options-matrix"
+
+// globalOptionID returns the id of the global option with the given name
+func globalOptionID(option string) string {
+	return "global-opt-" + toID(option)
+}
+
+// optionID returns the id of the option of the command with the given name in the ToC
+// (e.g. cmd-create-deployment-opt-replicas)
+func optionID(command string, option string) string {
+	return commandID(command) + "-opt-" + toID(option)
+}
+
+// toID returns s in lower case, with the sequences of characters other than letters and digits
+// replaced by a dash (e.g. create-deployment for create/deployment)
+func toID(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if dash && b.Len() > 0 {
+				b.WriteRune('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	return b.String()
+}
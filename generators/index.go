@@ -0,0 +1,109 @@
+/*
+Copyright 2019 Philippe Martin.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Types of the index terms, to build separate indexes with the index.on.type XSL parameter
+const (
+	commandIndex  = "command"
+	optionIndex   = "option"
+	resourceIndex = "resource"
+)
+
+// resourceTypes maps the names, plurals and short names of the Kubernetes resources
+// to the name displayed in the index
+var resourceTypes = map[string]string{}
+
+func init() {
+	for name, aliases := range map[string][]string{
+		"certificatesigningrequest": {"certificatesigningrequests", "csr"},
+		"clusterrole":               {"clusterroles"},
+		"clusterrolebinding":        {"clusterrolebindings"},
+		"configmap":                 {"configmaps", "cm"},
+		"cronjob":                   {"cronjobs", "cj"},
+		"customresourcedefinition":  {"customresourcedefinitions", "crd", "crds"},
+		"daemonset":                 {"daemonsets", "ds"},
+		"deployment":                {"deployments", "deploy"},
+		"endpoints":                 {},
+		"event":                     {"events"},
+		"horizontalpodautoscaler":   {"horizontalpodautoscalers", "hpa"},
+		"ingress":                   {"ingresses", "ing"},
+		"job":                       {"jobs"},
+		"limitrange":                {"limitranges", "limits"},
+		"namespace":                 {"namespaces", "ns"},
+		"networkpolicy":             {"networkpolicies", "netpol"},
+		"node":                      {"nodes"},
+		"persistentvolume":          {"persistentvolumes", "pv"},
+		"persistentvolumeclaim":     {"persistentvolumeclaims", "pvc"},
+		"pod":                       {"pods", "po"},
+		"poddisruptionbudget":       {"poddisruptionbudgets", "pdb"},
+		"priorityclass":             {"priorityclasses"},
+		"replicaset":                {"replicasets", "rs"},
+		"replicationcontroller":     {"replicationcontrollers", "rc"},
+		"resourcequota":             {"resourcequotas", "quota"},
+		"role":                      {"roles"},
+		"rolebinding":               {"rolebindings"},
+		"secret":                    {"secrets"},
+		"service":                   {"services", "svc"},
+		"serviceaccount":            {"serviceaccounts", "sa"},
+		"statefulset":               {"statefulsets", "sts"},
+		"storageclass":              {"storageclasses", "sc"},
+	} {
+		resourceTypes[name] = name
+		for _, alias := range aliases {
+			resourceTypes[alias] = name
+		}
+	}
+}
+
+// indexTermAsDocbook writes an indexterm, with a secondary term if secondary is not empty
+func indexTermAsDocbook(w io.Writer, indent string, typ string, primary string, secondary string) {
+	fmt.Fprintf(w, "%s<indexterm type=\"%s\"><primary>%s</primary>", indent, typ, escapeXml(primary))
+	if len(secondary) > 0 {
+		fmt.Fprintf(w, "<secondary>%s</secondary>", escapeXml(secondary))
+	}
+	fmt.Fprint(w, "</indexterm>\n")
+}
+
+// GetExampleResources returns the resource types used in the examples of the command,
+// sorted and without duplicates (e.g. [deployment pod])
+func (o *Command) GetExampleResources() (resources []string) {
+	found := map[string]bool{}
+	for _, example := range o.Examples {
+		for _, word := range strings.Fields(example.Content) {
+			if strings.HasPrefix(word, "-") {
+				continue
+			}
+			// TYPE/NAME or TYPE1,TYPE2
+			word = strings.SplitN(word, "/", 2)[0]
+			for _, name := range strings.Split(word, ",") {
+				if resource, ok := resourceTypes[strings.ToLower(name)]; ok && !found[resource] {
+					found[resource] = true
+					resources = append(resources, resource)
+				}
+			}
+		}
+	}
+	sort.Strings(resources)
+	return
+}
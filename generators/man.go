@@ -0,0 +1,176 @@
+/*
+Copyright 2019 Philippe Martin.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var manSynopsisStyle = synopsisStyle{
+	literal:     func(s string) string { return `\fB` + escapeRoff(s) + `\fR` },
+	replaceable: func(s string) string { return `\fI` + escapeRoff(s) + `\fR` },
+	newLine:     "\n.br\n\\ \\ ",
+}
+
+// GenerateMan writes the man pages of the Kubernetes version given in opts into dir,
+// one page per command
+func GenerateMan(dir string, opts *GenerateOptions) error {
+	toc, manifest, spec, err := load(opts)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, category := range toc.Categories {
+		for _, tocCommand := range category.Commands {
+			command := spec.GetCommand(tocCommand.Name)
+			if command == nil {
+				errs = append(errs, &CommandNotFoundError{Command: tocCommand.Name})
+				continue
+			}
+			if err := writeManPage(filepath.Join(dir, command.GetManPageName(opts.GetBinary())), command, tocCommand, manifest, opts); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// writeManPage writes the man page of the command, only if it can be rendered entirely
+func writeManPage(filename string, command *Command, config *ToCCommand, manifest *Manifest, opts *GenerateOptions) error {
+	var buf bytes.Buffer
+	if err := command.AsMan(&buf, config, manifest, opts); err != nil {
+		return err
+	}
+	return os.WriteFile(filename, buf.Bytes(), 0644)
+}
+
+// GetManPageName returns the name of the man page of the command (e.g. kubectl-create-deployment.1)
+func (o *Command) GetManPageName(binary string) string {
+	return strings.ReplaceAll(o.GetCommandLine(binary), " ", "-") + ".1"
+}
+
+func (o *Command) AsMan(w io.Writer, config *ToCCommand, manifest *Manifest, opts *GenerateOptions) error {
+	binary := opts.GetBinary()
+	name := strings.ReplaceAll(o.GetCommandLine(binary), " ", "-")
+	fmt.Fprintf(w, ".TH \"%s\" \"1\" \"\" \"%s\" \"%s\"\n", strings.ToUpper(name), escapeRoff(manifest.Subtitle), escapeRoff(manifest.Title))
+
+	fmt.Fprintf(w, ".SH %s\n", manSection(opts, "Name"))
+	fmt.Fprintf(w, "%s \\- %s\n", escapeRoff(name), escapeRoff(o.Synopsis))
+
+	// Synopsis
+	synopsis, err := o.synopsis(binary, config, manSynopsisStyle)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, ".SH %s\n%s\n", manSection(opts, "Synopsis"), synopsis)
+
+	if opts.ShowUsage {
+		fmt.Fprintf(w, ".SH %s\n.nf\n%s\n.fi\n", manSection(opts, "Original Usage"), escapeRoff(o.Usage))
+	}
+
+	// Description
+	fmt.Fprintf(w, ".SH %s\n", manSection(opts, "Description"))
+	for _, para := range strings.Split(o.Description, "\n\n") {
+		if para = strings.TrimSpace(para); len(para) > 0 {
+			fmt.Fprintf(w, ".PP\n%s\n", escapeRoff(para))
+		}
+	}
+
+	// Options
+	if len(config.OptionsGroups) > 0 {
+		fmt.Fprintf(w, ".SH %s\n", manSection(opts, "Options"))
+		for _, group := range config.OptionsGroups {
+			if len(group.Options) == 0 {
+				continue
+			}
+			if len(group.Name) > 0 {
+				fmt.Fprintf(w, ".SS %s\n", escapeRoff(group.Name))
+			}
+			for _, tocOption := range group.Options {
+				option := o.FindOption(tocOption.Name)
+				if option == nil {
+					return &OptionNotFoundError{Command: config.Name, Option: tocOption.Name}
+				}
+				option.AsManDetails(w, &tocOption, opts)
+			}
+		}
+	}
+
+	// Examples
+	if len(o.Examples) > 0 {
+		fmt.Fprintf(w, ".SH %s\n", manSection(opts, "Examples"))
+		for _, example := range o.Examples {
+			if len(example.Title) > 0 {
+				fmt.Fprintf(w, ".PP\n%s\n", escapeRoff(example.Title))
+			}
+			fmt.Fprintf(w, ".PP\n.RS\n.nf\n%s\n.fi\n.RE\n", escapeRoff(example.Content))
+		}
+	}
+	return nil
+}
+
+func (op *Option) AsManDetails(w io.Writer, config *ToCOption, opts *GenerateOptions) {
+	o := op.WithToC(config)
+
+	value := `\fB\-\-` + escapeRoff(o.Name) + `\fR`
+	if len(o.Shorthand) > 0 {
+		value = `\fB\-` + escapeRoff(o.Shorthand) + `\fR, ` + value
+	}
+
+	var def string
+	if len(o.DefaultValue) > 0 && o.DefaultValue != "[]" {
+		def = ", " + fmt.Sprintf(opts.T("defaults to %s"), escapeRoff(o.DefaultValue))
+	}
+	fmt.Fprintf(w, ".TP\n%s (%s%s)\n", value, o.Type, def)
+	if note := o.GetDeprecationNote(opts); len(note) > 0 {
+		fmt.Fprintf(w, "\\fB%s.\\fR\n", escapeRoff(note))
+	}
+	lines := strings.Split(strings.TrimSpace(o.Usage), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	fmt.Fprintf(w, "%s\n", escapeRoff(strings.Join(lines, "\n")))
+}
+
+// manSection returns the title of a section of a man page, translated and upper-cased (e.g. OPTIONS)
+func manSection(opts *GenerateOptions, title string) string {
+	return escapeRoff(strings.ToUpper(opts.T(title)))
+}
+
+// escapeRoff escapes s so it is rendered as is by roff
+func escapeRoff(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\e`)
+	s = strings.ReplaceAll(s, "-", `\-`)
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[i] = `\&` + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2019 Philippe Martin.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ReadManifest reads the manifest from a manifest.yaml file, and sets the default values
+// of the fields not defined, except the title and subtitle which depend on the product.
+// A missing file is not an error, all the default values are used
+func ReadManifest(filename string) (*Manifest, error) {
+	manifest := Manifest{}
+	contents, err := ioutil.ReadFile(filename)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read yaml file %s: %v", filename, err)
+	}
+	if err == nil {
+		err = yaml.Unmarshal(contents, &manifest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse yaml file %s: %v", filename, err)
+		}
+	}
+
+	if len(manifest.Copyright) == 0 {
+		manifest.Copyright = strconv.Itoa(time.Now().Year())
+	}
+	if len(manifest.Holder) == 0 {
+		manifest.Holder = "The Kubernetes Authors"
+	}
+	if len(manifest.Authors) == 0 {
+		manifest.Authors = "the Kubernetes Authors"
+	}
+	return &manifest, nil
+}
+
+// GetKubectlVersion returns the version of the kubectl compiled in (e.g. v1.31),
+// or an empty string if it cannot be determined
+func GetKubectlVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	for _, dep := range info.Deps {
+		if dep.Path != "k8s.io/kubectl" {
+			continue
+		}
+		// k8s.io/kubectl v0.X.Y is released with Kubernetes v1.X.Y
+		parts := strings.Split(strings.TrimPrefix(dep.Version, "v"), ".")
+		if len(parts) < 2 {
+			return dep.Version
+		}
+		return "v1." + parts[1]
+	}
+	return ""
+}
+
+// AsDocbook writes the bookinfo of the book, with its texts translated in the language of opts
+func (o *Manifest) AsDocbook(w io.Writer, opts *GenerateOptions) {
+	fmt.Fprintf(w, `  <bookinfo>
+    <title>%s</title>
+`, escapeXml(o.Title))
+
+	if len(o.Subtitle) > 0 {
+		fmt.Fprintf(w, `
+    <subtitle>%s</subtitle>
+`, escapeXml(o.Subtitle))
+	}
+
+	fmt.Fprintf(w, `
+    <releaseinfo>%s</releaseinfo>
+`, escapeXml(fmt.Sprintf(opts.T("By %s"), o.Authors)))
+
+	if len(o.Editor) > 0 {
+		fmt.Fprintf(w, `
+    <releaseinfo>%s</releaseinfo>
+`, escapeXml(fmt.Sprintf(opts.T("Edited and published by %s"), o.Editor)))
+	}
+
+	license := fmt.Sprintf(escapeXml(opts.T("Permission is granted to copy, distribute and/or modify this document under the terms of the Apache License version 2. A copy of the license is included in %s.")), `<xref linkend="license"/>`)
+	tool := escapeXml(fmt.Sprintf(opts.T("The tool used to generate this document is available at %s"), "https://github.com/feloy/kubectl-reference"))
+	fmt.Fprintf(w, `
+    <copyright>
+      <year>%s</year>
+
+      <holder>%s</holder>
+    </copyright>
+
+    <legalnotice>
+      <para>%s</para>
+    </legalnotice>
+
+    <legalnotice>
+      <para>%s</para>
+    </legalnotice>
+  </bookinfo>
+`, escapeXml(o.Copyright), escapeXml(o.Holder), license, tool)
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2019 Philippe Martin.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// GenerateMarkdown writes the Markdown reference of the Kubernetes version given in opts
+// into dir, as one page per command and an index page
+func GenerateMarkdown(dir string, opts *GenerateOptions) error {
+	toc, manifest, spec, err := load(opts)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	index, err := os.Create(filepath.Join(dir, "_index.md"))
+	if err != nil {
+		return err
+	}
+	defer index.Close()
+
+	fmt.Fprintf(index, "---\ntitle: %q\n---\n\n", manifest.Title)
+	if len(manifest.Subtitle) > 0 {
+		fmt.Fprintf(index, "%s\n", manifest.Subtitle)
+	}
+
+	var errs []error
+	for _, category := range toc.Categories {
+		fmt.Fprintf(index, "\n## %s\n\n", category.Name)
+
+		for _, tocCommand := range category.Commands {
+			command := spec.GetCommand(tocCommand.Name)
+			if command == nil {
+				errs = append(errs, &CommandNotFoundError{Command: tocCommand.Name})
+				continue
+			}
+			page := command.GetMarkdownPageName(opts.GetBinary())
+			if err := writeMarkdownPage(filepath.Join(dir, page), command, tocCommand, opts); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			fmt.Fprintf(index, "- [%s](%s): %s\n", command.GetCommandLine(opts.GetBinary()), page, command.Synopsis)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// writeMarkdownPage writes the page of the command, only if it can be rendered entirely
+func writeMarkdownPage(filename string, command *Command, config *ToCCommand, opts *GenerateOptions) error {
+	var buf bytes.Buffer
+	if err := command.AsMarkdown(&buf, config, opts); err != nil {
+		return err
+	}
+	return os.WriteFile(filename, buf.Bytes(), 0644)
+}
+
+// GetMarkdownPageName returns the name of the Markdown page of the command (e.g. kubectl_create_deployment.md)
+func (o *Command) GetMarkdownPageName(binary string) string {
+	return strings.ReplaceAll(o.GetCommandLine(binary), " ", "_") + ".md"
+}
+
+func (o *Command) AsMarkdown(w io.Writer, config *ToCCommand, opts *GenerateOptions) error {
+	fmt.Fprintf(w, "---\ntitle: %q\n---\n\n", o.GetCommandLine(opts.GetBinary()))
+	fmt.Fprintf(w, "%s\n\n", o.Synopsis)
+
+	// Usage
+	usage, err := o.TextSynopsis(opts.GetBinary(), config)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "## %s\n\n```\n%s\n```\n\n", opts.T("Usage"), usage)
+
+	if opts.ShowUsage {
+		fmt.Fprintf(w, "## %s\n\n```\n%s\n```\n\n", opts.T("Original Usage"), o.Usage)
+	}
+
+	// Description
+	if len(o.Description) > 0 {
+		fmt.Fprintf(w, "## %s\n\n%s\n\n", opts.T("Description"), strings.TrimSpace(o.Description))
+	}
+
+	// Options
+	if len(config.OptionsGroups) > 0 {
+		fmt.Fprintf(w, "## %s\n\n", opts.T("Options"))
+
+		for _, group := range config.OptionsGroups {
+			if len(group.Options) == 0 {
+				continue
+			}
+			if len(group.Name) > 0 {
+				fmt.Fprintf(w, "### %s\n\n", group.Name)
+			}
+			fmt.Fprintf(w, "| %s | %s | %s | %s |\n", opts.T("Option"), opts.T("Type"), opts.T("Default"), opts.T("Description"))
+			fmt.Fprint(w, "|--------|------|---------|-------------|\n")
+			for _, tocOption := range group.Options {
+				option := o.FindOption(tocOption.Name)
+				if option == nil {
+					return &OptionNotFoundError{Command: config.Name, Option: tocOption.Name}
+				}
+				option.AsMarkdownDetails(w, &tocOption, opts)
+			}
+			fmt.Fprint(w, "\n")
+		}
+	}
+
+	// Examples
+	if len(o.Examples) > 0 {
+		fmt.Fprintf(w, "## %s\n\n", opts.T("Examples"))
+		for _, example := range o.Examples {
+			if len(example.Title) > 0 {
+				fmt.Fprintf(w, "%s\n\n", example.Title)
+			}
+			fmt.Fprintf(w, "```shell\n%s\n```\n\n", example.Content)
+		}
+	}
+	return nil
+}
+
+func (op *Option) AsMarkdownDetails(w io.Writer, config *ToCOption, opts *GenerateOptions) {
+	o := op.WithToC(config)
+
+	value := "`--" + o.Name + "`"
+	if len(o.Shorthand) > 0 {
+		value = "`-" + o.Shorthand + "`, " + value
+	}
+
+	var def string
+	if len(o.DefaultValue) > 0 && o.DefaultValue != "[]" {
+		def = "`" + o.DefaultValue + "`"
+	}
+	usage := escapeMarkdownCell(o.Usage)
+	if note := o.GetDeprecationNote(opts); len(note) > 0 {
+		usage = "**" + escapeMarkdownCell(note) + ".** " + usage
+	}
+	fmt.Fprintf(w, "| %s | %s | %s | %s |\n", value, o.Type, def, usage)
+}
+
+// escapeMarkdownCell makes s fit in a single cell of a Markdown table
+func escapeMarkdownCell(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	for i, line := range lines {
+		lines[i] = strings.ReplaceAll(strings.TrimSpace(line), "|", "\\|")
+	}
+	return strings.Join(lines, "<br>")
+}
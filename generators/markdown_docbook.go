@@ -0,0 +1,195 @@
+/*
+Copyright 2019 Philippe Martin.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// markdownInlineRegexp matches the inline Markdown elements converted to DocBook:
+// `code`, [text](url), **bold** and bare URLs
+var markdownInlineRegexp = regexp.MustCompile("`[^`]+`|\\[[^\\]]+\\]\\([^)\\s]+\\)|\\*\\*[^*]+\\*\\*|https?://[^\\s<>\"'()\\[\\]]+")
+
+var markdownLinkRegexp = regexp.MustCompile(`^\[([^\]]+)\]\(([^)\s]+)\)$`)
+
+var markdownHeadingRegexp = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*$`)
+
+var markdownBulletRegexp = regexp.MustCompile(`^( {0,3})[-*+]\s+(.*)$`)
+
+var markdownOrderedRegexp = regexp.MustCompile(`^( {0,3})\d+[.)]\s+(.*)$`)
+
+// markdownToDocbook converts a Markdown text into DocBook block elements (para, bridgehead,
+// itemizedlist, orderedlist and programlisting), written to w with the given indentation
+func markdownToDocbook(w io.Writer, src string, indent string) {
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+
+	var para []string
+	flushPara := func() {
+		if len(para) > 0 {
+			fmt.Fprintf(w, "%s<para>%s</para>\n", indent, markdownInlineToDocbook(strings.Join(para, " ")))
+			para = nil
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case len(trimmed) == 0:
+			flushPara()
+
+		case strings.HasPrefix(trimmed, "```"):
+			flushPara()
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				code = append(code, lines[i])
+			}
+			fmt.Fprintf(w, "%s<programlisting>%s</programlisting>\n", indent, escapeXml(strings.Join(code, "\n")))
+
+		case markdownHeadingRegexp.MatchString(line):
+			flushPara()
+			matches := markdownHeadingRegexp.FindStringSubmatch(line)
+			level := len(matches[1])
+			if level > 5 {
+				level = 5
+			}
+			fmt.Fprintf(w, "%s<bridgehead renderas=\"sect%d\">%s</bridgehead>\n", indent, level, markdownInlineToDocbook(matches[2]))
+
+		case markdownBulletRegexp.MatchString(line):
+			flushPara()
+			i = markdownListToDocbook(w, lines, i, markdownBulletRegexp, "itemizedlist", indent)
+
+		case markdownOrderedRegexp.MatchString(line):
+			flushPara()
+			i = markdownListToDocbook(w, lines, i, markdownOrderedRegexp, "orderedlist", indent)
+
+		case len(para) == 0 && isMarkdownCodeLine(line):
+			var code []string
+			for ; i < len(lines); i++ {
+				if isMarkdownCodeLine(lines[i]) {
+					code = append(code, lines[i])
+				} else if len(strings.TrimSpace(lines[i])) == 0 && i+1 < len(lines) && isMarkdownCodeLine(lines[i+1]) {
+					code = append(code, "")
+				} else {
+					break
+				}
+			}
+			i--
+			fmt.Fprintf(w, "%s<programlisting>%s</programlisting>\n", indent, escapeXml(dedent(code)))
+
+		default:
+			para = append(para, trimmed)
+		}
+	}
+	flushPara()
+}
+
+// isMarkdownCodeLine returns true if the line is part of an indented code block
+func isMarkdownCodeLine(line string) bool {
+	return (strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t")) && len(strings.TrimSpace(line)) > 0
+}
+
+// markdownListToDocbook writes the list starting at lines[start] as a DocBook list,
+// and returns the index of its last line
+func markdownListToDocbook(w io.Writer, lines []string, start int, item *regexp.Regexp, element string, indent string) int {
+	var items []string
+	i := start
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		if matches := item.FindStringSubmatch(line); matches != nil {
+			items = append(items, matches[2])
+			continue
+		}
+		if len(trimmed) == 0 {
+			// a blank line ends the list, unless the next line is another item
+			if i+1 < len(lines) && item.MatchString(lines[i+1]) {
+				continue
+			}
+			break
+		}
+		if isIndented(line) || len(strings.TrimSpace(lines[i-1])) > 0 {
+			items[len(items)-1] += " " + trimmed
+			continue
+		}
+		break
+	}
+
+	fmt.Fprintf(w, "%s<%s>\n", indent, element)
+	for _, it := range items {
+		fmt.Fprintf(w, "%s  <listitem><para>%s</para></listitem>\n", indent, markdownInlineToDocbook(it))
+	}
+	fmt.Fprintf(w, "%s</%s>\n", indent, element)
+	return i - 1
+}
+
+func isIndented(line string) bool {
+	return (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(strings.TrimSpace(line)) > 0
+}
+
+// markdownInlineToDocbook escapes the text and converts its inline Markdown elements
+// into DocBook literal, ulink and emphasis elements
+func markdownInlineToDocbook(s string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range markdownInlineRegexp.FindAllStringIndex(s, -1) {
+		b.WriteString(escapeXml(s[last:loc[0]]))
+		token := s[loc[0]:loc[1]]
+		last = loc[1]
+
+		switch {
+		case strings.HasPrefix(token, "`"):
+			fmt.Fprintf(&b, "<literal>%s</literal>", escapeXml(strings.Trim(token, "`")))
+
+		case strings.HasPrefix(token, "["):
+			matches := markdownLinkRegexp.FindStringSubmatch(token)
+			fmt.Fprintf(&b, "<ulink url=\"%s\">%s</ulink>", escapeXml(matches[2]), escapeXml(matches[1]))
+
+		case strings.HasPrefix(token, "**"):
+			fmt.Fprintf(&b, "<emphasis role=\"bold\">%s</emphasis>", escapeXml(strings.Trim(token, "*")))
+
+		default:
+			// the punctuation ending a sentence is not part of the URL
+			url := strings.TrimRight(token, ".,;:!?")
+			last -= len(token) - len(url)
+			fmt.Fprintf(&b, "<ulink url=\"%s\">%s</ulink>", escapeXml(url), escapeXml(url))
+		}
+	}
+	b.WriteString(escapeXml(s[last:]))
+	return b.String()
+}
+
+// descriptionToDocbook converts the long description of a command into DocBook block elements.
+// The descriptions of kubectl are Markdown texts whose paragraphs are indented by one space
+// (e.g. " Valid resource types include:"), this space is removed so the lists and the blocks
+// indented by 4 spaces or more are recognized
+func descriptionToDocbook(w io.Writer, description string, indent string) {
+	lines := strings.Split(strings.ReplaceAll(description, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		switch {
+		case len(strings.TrimSpace(line)) == 0:
+			lines[i] = ""
+		case strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "  "):
+			lines[i] = line[1:]
+		}
+	}
+	markdownToDocbook(w, strings.Join(lines, "\n"), indent)
+}
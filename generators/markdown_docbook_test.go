@@ -0,0 +1,144 @@
+/*
+Copyright 2019 Philippe Martin.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMarkdownToDocbook(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "paragraphs joined and escaped",
+			src:  "First line\nsecond line & more.\n\nSecond <paragraph>.",
+			want: "<para>First line second line &amp; more.</para>\n<para>Second &lt;paragraph&gt;.</para>\n",
+		},
+		{
+			name: "heading",
+			src:  "## Getting started ##",
+			want: "<bridgehead renderas=\"sect2\">Getting started</bridgehead>\n",
+		},
+		{
+			name: "bullet list with continuation lines",
+			src:  "Types:\n\n* pods, the smallest\n  deployable units\n* services\ncontinued without indentation\n\nAfter the list.",
+			want: "<para>Types:</para>\n<itemizedlist>\n  <listitem><para>pods, the smallest deployable units</para></listitem>\n  <listitem><para>services continued without indentation</para></listitem>\n</itemizedlist>\n<para>After the list.</para>\n",
+		},
+		{
+			name: "ordered list with blank lines between items",
+			src:  "1. first\n\n2) second",
+			want: "<orderedlist>\n  <listitem><para>first</para></listitem>\n  <listitem><para>second</para></listitem>\n</orderedlist>\n",
+		},
+		{
+			name: "indented code",
+			src:  "Run:\n\n    kubectl get pods\n      -o wide\n\n    kubectl get nodes\n\nDone.",
+			want: "<para>Run:</para>\n<programlisting>kubectl get pods\n  -o wide\n\nkubectl get nodes</programlisting>\n<para>Done.</para>\n",
+		},
+		{
+			name: "indented line continuing a paragraph is not code",
+			src:  "A paragraph\n    continued",
+			want: "<para>A paragraph continued</para>\n",
+		},
+		{
+			name: "fenced code",
+			src:  "```\na < b\n```",
+			want: "<programlisting>a &lt; b</programlisting>\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			markdownToDocbook(&buf, tt.src, "")
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkdownInlineToDocbook(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "bare URL followed by a period",
+			src:  "See https://kubernetes.io/docs.",
+			want: `See <ulink url="https://kubernetes.io/docs">https://kubernetes.io/docs</ulink>.`,
+		},
+		{
+			name: "bare URL followed by a comma",
+			src:  "https://example.com/a?b=c, then",
+			want: `<ulink url="https://example.com/a?b=c">https://example.com/a?b=c</ulink>, then`,
+		},
+		{
+			name: "bare URL in parentheses",
+			src:  "(https://example.com)",
+			want: `(<ulink url="https://example.com">https://example.com</ulink>)`,
+		},
+		{
+			name: "link, code and bold",
+			src:  "Use `kubectl get` with **care**, see [the docs](https://kubernetes.io).",
+			want: `Use <literal>kubectl get</literal> with <emphasis role="bold">care</emphasis>, see <ulink url="https://kubernetes.io">the docs</ulink>.`,
+		},
+		{
+			name: "no markup",
+			src:  "a * b > c",
+			want: "a * b &gt; c",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := markdownInlineToDocbook(tt.src); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDescriptionToDocbook(t *testing.T) {
+	tests := []struct {
+		name        string
+		description string
+		want        string
+	}{
+		{
+			name:        "kubectl paragraphs and list indented by one space",
+			description: "Display one or many resources.\n\n Valid resource types include:\n\n  *  pods\n  *  services\n\n Use \"kubectl api-resources\" for a complete list.",
+			want:        "<para>Display one or many resources.</para>\n<para>Valid resource types include:</para>\n<itemizedlist>\n  <listitem><para>pods</para></listitem>\n  <listitem><para>services</para></listitem>\n</itemizedlist>\n<para>Use &#34;kubectl api-resources&#34; for a complete list.</para>\n",
+		},
+		{
+			name:        "kubectl code block",
+			description: " Examples:\n\n     kubectl get pods\n     kubectl get nodes",
+			want:        "<para>Examples:</para>\n<programlisting>kubectl get pods\nkubectl get nodes</programlisting>\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			descriptionToDocbook(&buf, tt.description, "")
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2019 Philippe Martin.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// OptionsMatrix lists, for each option, the commands accepting it
+type OptionsMatrix struct {
+	Options []MatrixOption `json:"options"`
+}
+
+// MatrixOption is an option with the commands accepting it
+type MatrixOption struct {
+	Name     string       `json:"name"`
+	Commands []MatrixCell `json:"commands"`
+}
+
+// MatrixCell is the definition of an option in a command
+type MatrixCell struct {
+	// Command is the name of the command, as in the ToC (e.g. create/deployment)
+	Command string `json:"command"`
+	Type    string `json:"type"`
+	Default string `json:"default,omitempty"`
+}
+
+// NewOptionsMatrix returns the matrix of the options of the commands of the spec, the global options
+// excepted. If options is not empty, only these options are part of the matrix. The hidden commands
+// and options are excepted, unless showHidden is true
+func NewOptionsMatrix(spec *KubectlSpec, options []string, showHidden bool) *OptionsMatrix {
+	filter := map[string]bool{}
+	for _, option := range options {
+		filter[option] = true
+	}
+
+	byName := map[string]*MatrixOption{}
+	for _, name := range spec.GetAllCommandNames() {
+		command := spec.GetCommand(name)
+		if command.Hidden && !showHidden {
+			continue
+		}
+		for _, option := range command.Options.visible(showHidden) {
+			if len(filter) > 0 && !filter[option.Name] {
+				continue
+			}
+			matrixOption, found := byName[option.Name]
+			if !found {
+				matrixOption = &MatrixOption{Name: option.Name}
+				byName[option.Name] = matrixOption
+			}
+			cell := MatrixCell{
+				Command: name,
+				Type:    option.Type,
+			}
+			if option.DefaultValue != "[]" {
+				cell.Default = option.DefaultValue
+			}
+			matrixOption.Commands = append(matrixOption.Commands, cell)
+		}
+	}
+
+	result := &OptionsMatrix{Options: []MatrixOption{}}
+	for _, option := range byName {
+		sort.Slice(option.Commands, func(i, j int) bool { return option.Commands[i].Command < option.Commands[j].Command })
+		result.Options = append(result.Options, *option)
+	}
+	sort.Slice(result.Options, func(i, j int) bool { return result.Options[i].Name < result.Options[j].Name })
+	return result
+}
+
+// GetCommandNames returns the names of the commands accepting at least one of the options of the matrix
+func (o *OptionsMatrix) GetCommandNames() (commands []string) {
+	found := map[string]bool{}
+	for _, option := range o.Options {
+		for _, cell := range option.Commands {
+			if !found[cell.Command] {
+				found[cell.Command] = true
+				commands = append(commands, cell.Command)
+			}
+		}
+	}
+	sort.Strings(commands)
+	return
+}
+
+// String returns the content of the cell in a table (e.g. "string (none)")
+func (o MatrixCell) String() string {
+	if len(o.Default) > 0 {
+		return fmt.Sprintf("%s (%s)", o.Type, o.Default)
+	}
+	return o.Type
+}
+
+// Matrix writes the options matrix of the Kubernetes version given in opts
+// to w in the given format (csv, json or docbook)
+func Matrix(w io.Writer, format string, options []string, opts *GenerateOptions) error {
+	_, _, spec, err := load(opts)
+	if err != nil {
+		return err
+	}
+
+	matrix := NewOptionsMatrix(spec, options, opts.ShowHidden)
+	switch format {
+	case "csv":
+		return matrix.AsCSV(w)
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(matrix)
+	case "docbook":
+		// the commands are not part of the document, so they are not linked
+		matrix.AsDocbook(w, nil, opts)
+		return nil
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+}
+
+// AsCSV writes the matrix with a row per option and a column per command
+func (o *OptionsMatrix) AsCSV(w io.Writer) error {
+	commands := o.GetCommandNames()
+	writer := csv.NewWriter(w)
+	if err := writer.Write(append([]string{"option"}, commands...)); err != nil {
+		return err
+	}
+	for _, option := range o.Options {
+		row := make([]string, len(commands)+1)
+		row[0] = option.Name
+		for _, cell := range option.Commands {
+			row[1+sort.SearchStrings(commands, cell.Command)] = cell.String()
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// AsDocbook writes the matrix as an appendix, with a table listing the commands accepting each option.
+// The commands of toc, the ones rendered in the book, are linked to their reference. No command is linked
+// if toc is nil
+func (o *OptionsMatrix) AsDocbook(w io.Writer, toc *ToC, opts *GenerateOptions) {
+	fmt.Fprintf(w, `  <appendix id="%s"><title>%s</title>
+    <informaltable>
+      <tgroup cols="3">
+        <thead>
+          <row><entry>%s</entry><entry>%s</entry><entry>%s</entry></row>
+        </thead>
+        <tbody>
+`, optionsMatrixID, opts.T("Options by command"), opts.T("Option"), opts.T("Command"), opts.T("Type (default)"))
+	for _, option := range o.Options {
+		for i, cell := range option.Commands {
+			fmt.Fprint(w, "          <row>")
+			if i == 0 {
+				fmt.Fprintf(w, "<entry morerows=\"%d\"><option>--%s</option></entry>", len(option.Commands)-1, option.Name)
+			}
+			command := escapeXml(strings.ReplaceAll(cell.Command, "/", " "))
+			if toc != nil {
+				if _, tocCommand := toc.GetCommand(cell.Command); tocCommand != nil {
+					id := commandID(cell.Command)
+					if _, tocOption := tocCommand.GetOption(option.Name); tocOption != nil {
+						id = optionID(cell.Command, option.Name)
+					}
+					command = fmt.Sprintf("<link linkend=\"%s\">%s</link>", id, command)
+				}
+			}
+			fmt.Fprintf(w, "<entry>%s</entry><entry>%s</entry></row>\n", command, escapeXml(cell.String()))
+		}
+	}
+	fmt.Fprint(w, `        </tbody>
+      </tgroup>
+    </informaltable>
+  </appendix>
+`)
+}
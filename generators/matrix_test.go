@@ -0,0 +1,90 @@
+/*
+Copyright 2019 Philippe Martin.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"reflect"
+	"testing"
+)
+
+// hiddenTestSpec returns a spec with a hidden command, a hidden option,
+// a hidden global option and a deprecated hidden option
+func hiddenTestSpec() *KubectlSpec {
+	global := Options{
+		{Name: "namespace", Type: "string"},
+		{Name: "profile", Type: "string", Hidden: true},
+	}
+	return &KubectlSpec{
+		TopLevelCommandGroups: []TopLevelCommands{{
+			Commands: []TopLevelCommand{
+				{MainCommand: &Command{
+					Name: "get",
+					Options: Options{
+						{Name: "output", Type: "string"},
+						{Name: "record", Type: "bool", Hidden: true, Deprecated: "will be removed"},
+						{Name: "server-print", Type: "bool", Hidden: true},
+					},
+					InheritedOptions: global,
+				}},
+				{MainCommand: &Command{
+					Name:             "internal",
+					Hidden:           true,
+					Options:          Options{{Name: "output", Type: "string"}},
+					InheritedOptions: global,
+				}},
+			},
+		}},
+	}
+}
+
+func TestNewOptionsMatrixHidden(t *testing.T) {
+	tests := []struct {
+		name       string
+		showHidden bool
+		want       map[string][]string
+	}{
+		{
+			name: "hidden excepted",
+			want: map[string][]string{
+				"output": {"get"},
+				"record": {"get"},
+			},
+		},
+		{
+			name:       "hidden shown",
+			showHidden: true,
+			want: map[string][]string{
+				"output":       {"get", "internal"},
+				"record":       {"get"},
+				"server-print": {"get"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := map[string][]string{}
+			for _, option := range NewOptionsMatrix(hiddenTestSpec(), nil, tt.showHidden).Options {
+				for _, cell := range option.Commands {
+					got[option.Name] = append(got[option.Name], cell.Command)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
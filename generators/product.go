@@ -0,0 +1,98 @@
+/*
+Copyright 2019 Philippe Martin.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/kubectl/pkg/cmd"
+)
+
+// DefaultProduct is the product documented when none is specified
+const DefaultProduct = "kubectl"
+
+// Product is a cobra-based CLI to document
+type Product struct {
+	// Name is the name of the product, used in the default title of the book (e.g. Kubectl)
+	Name string
+	// Binary is the command line prefix of the commands (e.g. kubectl, or "kubectl foo" for a plugin).
+	// It identifies the product
+	Binary string
+	// NewCommand returns the root command of the CLI
+	NewCommand func() *cobra.Command
+	// Help returns the help texts of the commands, for a CLI which is not compiled in.
+	// It is used when NewCommand is nil
+	Help HelpFunc
+}
+
+var products = map[string]*Product{}
+
+func init() {
+	RegisterProduct(&Product{
+		Name:       "Kubectl",
+		Binary:     "kubectl",
+		NewCommand: cmd.NewDefaultKubectlCommand,
+	})
+}
+
+// RegisterProduct registers a product, to be documented with the --product flag set to its binary
+func RegisterProduct(product *Product) {
+	products[product.Binary] = product
+}
+
+// GetProduct returns the registered product with the given binary
+func GetProduct(binary string) (*Product, error) {
+	if len(binary) == 0 {
+		binary = DefaultProduct
+	}
+	product, found := products[binary]
+	if !found {
+		return nil, fmt.Errorf("unknown product %q, must be one of %v", binary, GetProductBinaries())
+	}
+	return product, nil
+}
+
+// GetProductBinaries returns the binaries of the registered products
+func GetProductBinaries() (binaries []string) {
+	for binary := range products {
+		binaries = append(binaries, binary)
+	}
+	sort.Strings(binaries)
+	return
+}
+
+// GetSpec returns the spec extracted from the command tree of the product,
+// or parsed from the help texts of its commands
+func (o *Product) GetSpec() (KubectlSpec, error) {
+	if o.NewCommand == nil {
+		spec, err := NewSpecFromHelp(o.Help)
+		if err != nil {
+			return KubectlSpec{}, err
+		}
+		spec.Product = o.Binary
+		return spec, nil
+	}
+	spec := NewKubectlSpec(o.NewCommand())
+	spec.Product = o.Binary
+	if o.Binary == DefaultProduct {
+		spec.KubectlVersion = GetKubectlVersion()
+	}
+	return spec, nil
+}
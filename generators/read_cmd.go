@@ -18,25 +18,49 @@ limitations under the License.
 package generators
 
 import (
-	// "io/ioutil"
-	// "os"
-
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
 	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
-
-	"k8s.io/kubectl/pkg/cmd"
-	// cmdutil "k8s.io/kubernetes/pkg/kubectl/cmd/util"
+	"gopkg.in/yaml.v2"
 )
 
+// GetSpec returns the spec extracted from the kubectl command tree
 func GetSpec() KubectlSpec {
-	// Initialize a kubectl command that we can use to get the help documentation
-	kubectl := cmd.NewDefaultKubectlCommand()
+	kubectl, _ := GetProduct(DefaultProduct)
+	// the spec of a compiled in product cannot fail
+	spec, _ := kubectl.GetSpec()
+	return spec
+}
+
+// WriteSpec writes the spec as yaml, to be read later with ReadSpec
+func WriteSpec(w io.Writer, spec *KubectlSpec) error {
+	bytes, err := yaml.Marshal(spec)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(bytes)
+	return err
+}
+
+// ReadSpec reads a spec from a yaml file
+func ReadSpec(filename string) (*KubectlSpec, error) {
+	contents, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read yaml file %s: %v", filename, err)
+	}
 
-	// Create the structural representation
-	return NewKubectlSpec(kubectl)
+	spec := KubectlSpec{}
+	err = yaml.Unmarshal(contents, &spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse yaml file %s: %v", filename, err)
+	}
+	return &spec, nil
 }
 
 func NewKubectlSpec(c *cobra.Command) KubectlSpec {
@@ -70,17 +94,43 @@ func NewOptions(flags *pflag.FlagSet) Options {
 	result := Options{}
 	flags.VisitAll(func(flag *pflag.Flag) {
 		opt := &Option{
-			Name:         flag.Name,
-			Shorthand:    flag.Shorthand,
-			DefaultValue: flag.DefValue,
-			Usage:        flag.Usage,
-			Type:         flag.Value.Type(),
+			Name:                flag.Name,
+			Shorthand:           flag.Shorthand,
+			DefaultValue:        withoutHomeDir(flag.DefValue),
+			Usage:               flag.Usage,
+			Type:                flag.Value.Type(),
+			Deprecated:          flag.Deprecated,
+			ShorthandDeprecated: flag.ShorthandDeprecated,
+			Hidden:              flag.Hidden,
 		}
 		result = append(result, opt)
 	})
 	return result
 }
 
+// withoutHomeDir replaces the home directory of the current user by $HOME in s,
+// so the default values do not depend on the machine generating the docs
+func withoutHomeDir(s string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return s
+	}
+	return replaceHomeDir(s, home)
+}
+
+// replaceHomeDir replaces home by $HOME in s, if s is home or a path inside home.
+// An empty or root home is never replaced
+func replaceHomeDir(s string, home string) string {
+	home = strings.TrimSuffix(home, "/")
+	if len(home) == 0 {
+		return s
+	}
+	if s == home || strings.HasPrefix(s, home+"/") {
+		return "$HOME" + strings.TrimPrefix(s, home)
+	}
+	return s
+}
+
 // Parse the Commands
 func NewSubCommands(c *cobra.Command, path string) Commands {
 	subCommands := Commands{NewCommand(c, path)}
@@ -91,6 +141,19 @@ func NewSubCommands(c *cobra.Command, path string) Commands {
 }
 
 func NewCommand(c *cobra.Command, path string) *Command {
+	var children, siblings []string
+	for _, child := range c.Commands() {
+		if child.IsAvailableCommand() {
+			children = append(children, child.Name())
+		}
+	}
+	if len(path) > 0 && c.HasParent() {
+		for _, sibling := range c.Parent().Commands() {
+			if sibling != c && sibling.IsAvailableCommand() {
+				siblings = append(siblings, sibling.Name())
+			}
+		}
+	}
 	return &Command{
 		Name:             c.Name(),
 		Path:             path,
@@ -99,8 +162,30 @@ func NewCommand(c *cobra.Command, path string) *Command {
 		Examples:         SplitExamples(c.Example),
 		Options:          NewOptions(c.NonInheritedFlags()),
 		InheritedOptions: NewOptions(c.InheritedFlags()),
+		SeeAlso:          newSeeAlso(path, c.Name(), children, siblings),
 		Usage:            c.Use,
+		Aliases:          c.Aliases,
+		Deprecated:       c.Deprecated,
+		SuggestFor:       c.SuggestFor,
+		Hidden:           c.Hidden,
+	}
+}
+
+// newSeeAlso returns the names of the commands related to the command with the given path and name:
+// its parent, its children and its siblings, with the names given as in the ToC (e.g. create/deployment)
+func newSeeAlso(path string, name string, children []string, siblings []string) (seeAlso []string) {
+	fullName := name
+	if len(path) > 0 {
+		seeAlso = append(seeAlso, path)
+		fullName = path + "/" + name
+	}
+	for _, child := range children {
+		seeAlso = append(seeAlso, fullName+"/"+child)
 	}
+	for _, sibling := range siblings {
+		seeAlso = append(seeAlso, path+"/"+sibling)
+	}
+	return
 }
 
 func (a Options) Len() int      { return len(a) }
@@ -120,47 +205,3 @@ func (a Commands) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
 func (a Commands) Less(i, j int) bool {
 	return a[i].Path < a[j].Path
 }
-
-const (
-	start = iota
-	title
-	content
-)
-
-func SplitExamples(examples string) (result []Example) {
-	lines := strings.Split(examples, "\n")
-	pos := start
-	currentExample := Example{}
-	for _, line := range lines {
-		line = escapeXml(strings.Trim(line, " "))
-		if len(line) == 0 {
-			continue
-		}
-
-		if line[0] == '#' {
-			if pos == title {
-				line = strings.TrimLeft(line, "# ")
-				currentExample.Title += "\n " + line
-			} else {
-				if len(currentExample.Title) > 0 || len(currentExample.Content) > 0 {
-					result = append(result, currentExample)
-				}
-				currentExample = Example{}
-				line = strings.TrimLeft(line, "# ")
-				currentExample.Title = line
-				pos = title
-			}
-		} else {
-			if pos == content {
-				currentExample.Content += "\n" + line
-			} else {
-				currentExample.Content += line
-				pos = content
-			}
-		}
-	}
-	if len(currentExample.Title) > 0 || len(currentExample.Content) > 0 {
-		result = append(result, currentExample)
-	}
-	return
-}
@@ -0,0 +1,41 @@
+/*
+Copyright 2019 Philippe Martin.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import "testing"
+
+func TestReplaceHomeDir(t *testing.T) {
+	tests := []struct {
+		s    string
+		home string
+		want string
+	}{
+		{s: "/home/user/.kube/config", home: "/home/user", want: "$HOME/.kube/config"},
+		{s: "/home/user", home: "/home/user", want: "$HOME"},
+		{s: "/home/user/.kube/cache", home: "/home/user/", want: "$HOME/.kube/cache"},
+		{s: "/home/username/.kube", home: "/home/user", want: "/home/username/.kube"},
+		{s: "/tmp/home/user/.kube", home: "/home/user", want: "/tmp/home/user/.kube"},
+		{s: "/root/.kube/cache", home: "/", want: "/root/.kube/cache"},
+		{s: "/root/.kube/cache", home: "", want: "/root/.kube/cache"},
+		{s: "", home: "/home/user", want: ""},
+	}
+	for _, tt := range tests {
+		if got := replaceHomeDir(tt.s, tt.home); got != tt.want {
+			t.Errorf("replaceHomeDir(%q, %q) = %q, want %q", tt.s, tt.home, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2019 Philippe Martin.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import "strings"
+
+// synopsisStyle decorates the parts of a synopsis, for a given output format
+type synopsisStyle struct {
+	literal     func(string) string
+	replaceable func(string) string
+	// newLine starts a new indented line
+	newLine string
+}
+
+var textSynopsisStyle = synopsisStyle{
+	literal:     func(s string) string { return s },
+	replaceable: func(s string) string { return s },
+	newLine:     "\n  ",
+}
+
+// TextSynopsis returns the synopsis of the command as plain text,
+// with a line per group of options
+func (o *Command) TextSynopsis(binary string, config *ToCCommand) (string, error) {
+	return o.synopsis(binary, config, textSynopsisStyle)
+}
+
+func (o *Command) synopsis(binary string, config *ToCCommand, style synopsisStyle) (string, error) {
+	usage := style.literal(o.GetCommandLine(binary))
+	for _, arg := range config.Args {
+		if !arg.End {
+			usage += " " + arg.synopsis(style)
+		}
+	}
+	for _, group := range config.OptionsGroups {
+		if len(group.Options) == 0 {
+			continue
+		}
+		sep := style.newLine
+		for _, tocOption := range group.Options {
+			option := o.FindOption(tocOption.Name)
+			if option == nil {
+				return "", &OptionNotFoundError{Command: config.Name, Option: tocOption.Name}
+			}
+			usage += sep + option.synopsis(&tocOption, style)
+			sep = " "
+		}
+	}
+	sep := style.newLine
+	for _, arg := range config.Args {
+		if arg.End {
+			usage += sep + arg.synopsis(style)
+			sep = " "
+		}
+	}
+	return usage, nil
+}
+
+// synopsis returns the argument as it appears in a synopsis
+func (o *Arg) synopsis(style synopsisStyle) string {
+	value := style.replaceable(o.Name)
+	if o.Rep != nil && *o.Rep == "repeat" {
+		value += "..."
+	}
+	if o.Choice != nil {
+		switch *o.Choice {
+		case "opt":
+			return "[" + value + "]"
+		case "req":
+			return "{" + value + "}"
+		}
+	}
+	return value
+}
+
+// synopsis returns the option as it appears in a synopsis
+func (op *Option) synopsis(config *ToCOption, style synopsisStyle) string {
+	o := op.WithToC(config)
+
+	optional := func(value string) string {
+		if config.Required {
+			return value
+		}
+		return "[" + value + "]"
+	}
+
+	optionName := style.literal("--"+o.Name) + "="
+	if len(o.Shorthand) > 0 {
+		optionName = style.literal("-"+o.Shorthand) + " "
+	}
+
+	switch o.Type {
+	case "bool", "tristate":
+		var value string
+		if len(o.Shorthand) > 0 && o.DefaultValue == "false" {
+			value = "-" + o.Shorthand
+		} else {
+			value = "--" + o.Name
+			if o.DefaultValue == "true" {
+				value += "=false"
+			}
+		}
+		return optional(style.literal(value))
+
+	case "string":
+		if values := op.GetValues(config); len(values) > 0 {
+			alternatives := make([]string, len(values))
+			for i, value := range values {
+				alternatives[i] = style.literal(value)
+			}
+			return optional(optionName + "{" + strings.Join(alternatives, "|") + "}")
+		}
+		return optional(optionName + style.replaceable("value"))
+
+	case "int32", "int64", "int", "duration", "mapStringString":
+		return optional(optionName + style.replaceable("value"))
+
+	case "stringArray":
+		return optional(optionName+style.replaceable("value")) + "..."
+
+	case "stringToString":
+		return optional(optionName + style.replaceable("key1=value1") + "[," + style.replaceable("keyN=valueN") + "]...")
+
+	case "stringSlice":
+		return optional(optionName + style.replaceable("value1") + "[," + style.replaceable("valueN") + "]...")
+
+	default:
+		return "[" + style.literal("--"+o.Name) + "]"
+	}
+}
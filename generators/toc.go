@@ -16,25 +16,46 @@ limitations under the License.
 package generators
 
 import (
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
+
+	"gopkg.in/yaml.v2"
 )
 
 type ToC struct {
 	Categories []*Category `yaml:",omitempty"`
+	// InlineGlobalOptions lists the global options documented with every command,
+	// in addition to the appendix of the global options
+	InlineGlobalOptions []string `yaml:"inline_global_options,omitempty"`
 }
 
 type Category struct {
 	Name     string        `yaml:",omitempty"`
+	ID       string        `yaml:"id,omitempty"` // overrides the name to derive the id, when two names give the same id
 	Commands []*ToCCommand `yaml:",omitempty"`
 	Include  string        `yaml:",omitempty"`
 }
 
+// GetID returns the id of the category (e.g. cat-basic-commands-beginner)
+func (o *Category) GetID() string {
+	if len(o.ID) > 0 {
+		return categoryID(o.ID)
+	}
+	return categoryID(o.Name)
+}
+
 type ToCCommand struct {
 	Name          string         `yaml:",omitempty"`
 	Usage         string         `yaml:",omitempty"`
 	Args          []Arg          `yaml:",omitempty"`
 	OptionsGroups []OptionsGroup `yaml:"optionsgroups,omitempty"`
+	// SeeAlso lists related commands, in addition to the parent, children and siblings of the command
+	SeeAlso []string `yaml:"see_also,omitempty"`
+	// InlineGlobalOptions lists the global options documented with the command, in addition
+	// to the ones listed for all the commands
+	InlineGlobalOptions []string `yaml:"inline_global_options,omitempty"`
 }
 
 type Arg struct {
@@ -47,6 +68,8 @@ type Arg struct {
 type OptionsGroup struct {
 	Name    string      `yaml:",omitempty"`
 	Options []ToCOption `yaml:",omitempty"`
+	// inlined is true for the group of global options added by inlineGlobalOptions
+	inlined bool
 }
 
 type ToCOption struct {
@@ -56,6 +79,23 @@ type ToCOption struct {
 	Usage     *string `yaml:",omitempty"`
 	Shorthand *string `yaml:",omitempty"`
 	Default   *string `yaml:",omitempty"`
+	// Values lists the values accepted by the option, when they are not detected from its usage
+	Values []string `yaml:"values,omitempty"`
+}
+
+// ReadToC reads the ToC from a toc.yaml file
+func ReadToC(filename string) (*ToC, error) {
+	contents, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read yaml file %s: %v", filename, err)
+	}
+
+	toc := ToC{}
+	err = yaml.Unmarshal(contents, &toc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse yaml file %s: %v", filename, err)
+	}
+	return &toc, nil
 }
 
 func (o *ToC) GetAllCommandNames() (commands []string) {
@@ -67,6 +107,106 @@ func (o *ToC) GetAllCommandNames() (commands []string) {
 	return
 }
 
+// GetCommand returns the command of the ToC with the given name, and its category,
+// or nils if the command is not part of the ToC
+func (o *ToC) GetCommand(name string) (*Category, *ToCCommand) {
+	for _, category := range o.Categories {
+		for _, command := range category.Commands {
+			if command.Name == name {
+				return category, command
+			}
+		}
+	}
+	return nil, nil
+}
+
+// inlineGlobalOptions adds to each command a group with the given name, with the global options
+// to document with the command, skipping the ones already part of a group of the command.
+// The options listed for all the commands but not found in the spec are skipped, Validate reports them
+func (o *ToC) inlineGlobalOptions(groupName string, spec *KubectlSpec) {
+	var names []string
+	for _, name := range o.InlineGlobalOptions {
+		if spec.GetGlobalOption(name) != nil {
+			names = append(names, name)
+		}
+	}
+	for _, category := range o.Categories {
+		for _, command := range category.Commands {
+			group := OptionsGroup{Name: groupName, inlined: true}
+			for _, name := range append(append([]string{}, names...), command.InlineGlobalOptions...) {
+				if g, _ := command.GetOption(name); g != nil {
+					continue
+				}
+				group.Options = append(group.Options, ToCOption{Name: name})
+			}
+			if len(group.Options) > 0 {
+				command.OptionsGroups = append(command.OptionsGroups, group)
+			}
+		}
+	}
+}
+
+// removeCommands removes the commands for which skip returns true, and the categories left empty
+func (o *ToC) removeCommands(skip func(command *ToCCommand) bool) {
+	var categories []*Category
+	for _, category := range o.Categories {
+		var commands []*ToCCommand
+		for _, tocCommand := range category.Commands {
+			if !skip(tocCommand) {
+				commands = append(commands, tocCommand)
+			}
+		}
+		if len(commands) == 0 && len(category.Commands) > 0 {
+			continue
+		}
+		category.Commands = commands
+		categories = append(categories, category)
+	}
+	o.Categories = categories
+}
+
+// removeHiddenOptions removes from the commands the options hidden in the spec,
+// and the groups of options left empty
+func (o *ToC) removeHiddenOptions(spec *KubectlSpec) {
+	for _, category := range o.Categories {
+		for _, command := range category.Commands {
+			specCommand := spec.GetCommand(command.Name)
+			if specCommand == nil {
+				continue
+			}
+			var groups []OptionsGroup
+			for _, group := range command.OptionsGroups {
+				var options []ToCOption
+				for _, tocOption := range group.Options {
+					if option := specCommand.FindOption(tocOption.Name); option == nil || !option.IsHidden() {
+						options = append(options, tocOption)
+					}
+				}
+				if len(options) == 0 && len(group.Options) > 0 {
+					continue
+				}
+				group.Options = options
+				groups = append(groups, group)
+			}
+			command.OptionsGroups = groups
+		}
+	}
+}
+
+// GetOption returns the option of the command with the given name, and its group,
+// or nils if the option is not part of the command
+func (o *ToCCommand) GetOption(name string) (*OptionsGroup, *ToCOption) {
+	for g := range o.OptionsGroups {
+		group := &o.OptionsGroups[g]
+		for i := range group.Options {
+			if group.Options[i].Name == name {
+				return group, &group.Options[i]
+			}
+		}
+	}
+	return nil, nil
+}
+
 func (o *ToCCommand) GetAllOptionNames() (options []string) {
 	for _, group := range o.OptionsGroups {
 		for _, option := range group.Options {
@@ -89,6 +229,9 @@ func (o *ToC) AddMissingCommands(spec *KubectlSpec) {
 	}
 
 	for _, c := range spec.GetAllCommandNames() {
+		if spec.GetCommand(c).Hidden {
+			continue
+		}
 		if _, found := commandsInToC[c]; !found {
 			fmt.Fprintf(os.Stderr, "command %s not found\n", c)
 			categoryOthers.Commands = append(categoryOthers.Commands, &ToCCommand{
@@ -103,28 +246,29 @@ func (o *ToC) AddMissingCommands(spec *KubectlSpec) {
 
 }
 
-func (o *ToC) AddMissingOptions(spec *KubectlSpec) {
+// AddMissingOptions adds the options missing in the commands of the ToC.
+// The commands of the ToC not found in the spec are skipped, and returned as errors
+func (o *ToC) AddMissingOptions(spec *KubectlSpec) error {
+	var errs []error
 	for _, cats := range o.Categories {
 		for _, command := range cats.Commands {
 			cmd := spec.GetCommand(command.Name)
 			if cmd == nil {
-				fmt.Fprintln(os.Stderr, command.Name)
+				errs = append(errs, &CommandNotFoundError{Command: command.Name})
 				continue
 			}
 			command.AddMissingOptions(cmd)
 		}
 	}
+	return errors.Join(errs...)
 }
 
+// AddMissingUsages sets the usages of the commands of the ToC.
+// The commands of the ToC not found in the spec are skipped, they are reported by AddMissingOptions
 func (o *ToC) AddMissingUsages(spec *KubectlSpec) {
 	for _, cats := range o.Categories {
 		for _, command := range cats.Commands {
-			cmd := spec.GetCommand(command.Name)
-			if cmd == nil {
-				fmt.Fprintln(os.Stderr, command.Name)
-				continue
-			}
-			command.AddMissingUsage(cmd)
+			command.AddMissingUsage(spec.GetCommand(command.Name))
 		}
 	}
 }
@@ -140,6 +284,9 @@ func (o *ToCCommand) AddMissingOptions(spec *Command) {
 	}
 
 	for _, opt := range spec.GetAllOptionNames() {
+		if spec.GetOption(opt).IsHidden() {
+			continue
+		}
 		if _, found := optionsInToC[opt]; !found {
 			fmt.Fprintf(os.Stderr, "option %s not found in %s\n", opt, o.Name)
 			newGroup.Options = append(newGroup.Options, ToCOption{
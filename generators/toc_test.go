@@ -0,0 +1,66 @@
+/*
+Copyright 2019 Philippe Martin.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInlineGlobalOptions(t *testing.T) {
+	toc := &ToC{
+		InlineGlobalOptions: []string{"namespace", "nmespace"},
+		Categories: []*Category{{
+			Name: "Basic Commands",
+			Commands: []*ToCCommand{
+				{
+					Name:                "get",
+					OptionsGroups:       []OptionsGroup{{Name: "Options", Options: []ToCOption{{Name: "output"}}}},
+					InlineGlobalOptions: []string{"profile"},
+				},
+				{
+					Name:          "run",
+					OptionsGroups: []OptionsGroup{{Name: "Options", Options: []ToCOption{{Name: "namespace"}}}},
+				},
+			},
+		}},
+	}
+	toc.inlineGlobalOptions("Global options", hiddenTestSpec())
+
+	tests := []struct {
+		command string
+		want    []string
+	}{
+		{command: "get", want: []string{"namespace", "profile"}},
+		{command: "run", want: nil},
+	}
+	for _, tt := range tests {
+		_, command := toc.GetCommand(tt.command)
+		var got []string
+		for _, group := range command.OptionsGroups {
+			if !group.inlined {
+				continue
+			}
+			for _, option := range group.Options {
+				got = append(got, option.Name)
+			}
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.command, got, tt.want)
+		}
+	}
+}
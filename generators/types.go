@@ -17,7 +17,15 @@ limitations under the License.
 
 package generators
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
 type KubectlSpec struct {
+	Product               string             `yaml:",omitempty"`
+	KubectlVersion        string             `yaml:"kubectl_version,omitempty"`
 	TopLevelCommandGroups []TopLevelCommands `yaml:",omitempty"`
 }
 
@@ -37,6 +45,36 @@ func (o *KubectlSpec) GetCommand(name string) *Command {
 	return nil
 }
 
+// GetGlobalOptions returns the global options, the persistent options of the root command
+// inherited by all the top level commands. The hidden options are excepted, unless showHidden is true
+func (o *KubectlSpec) GetGlobalOptions(showHidden bool) Options {
+	result := Options{}
+	found := map[string]bool{}
+	for _, tlCommands := range o.TopLevelCommandGroups {
+		for _, command := range tlCommands.Commands {
+			for _, option := range command.MainCommand.InheritedOptions.visible(showHidden) {
+				if !found[option.Name] {
+					found[option.Name] = true
+					result = append(result, option)
+				}
+			}
+		}
+	}
+	sort.Sort(result)
+	return result
+}
+
+// GetGlobalOption returns the global option with the given name, hidden or not,
+// or nil if it is not found
+func (o *KubectlSpec) GetGlobalOption(name string) *Option {
+	for _, option := range o.GetGlobalOptions(true) {
+		if option.Name == name {
+			return option
+		}
+	}
+	return nil
+}
+
 func (o *KubectlSpec) GetAllCommandNames() (commands []string) {
 	for _, tlCommands := range o.TopLevelCommandGroups {
 		for _, command := range tlCommands.Commands {
@@ -60,11 +98,14 @@ type TopLevelCommand struct {
 
 type Options []*Option
 type Option struct {
-	Name         string `yaml:",omitempty"`
-	Shorthand    string `yaml:",omitempty"`
-	DefaultValue string `yaml:"default_value,omitempty"`
-	Usage        string `yaml:",omitempty"`
-	Type         string `yaml:",omitempty"`
+	Name                string `yaml:",omitempty"`
+	Shorthand           string `yaml:",omitempty"`
+	DefaultValue        string `yaml:"default_value,omitempty"`
+	Usage               string `yaml:",omitempty"`
+	Type                string `yaml:",omitempty"`
+	Deprecated          string `yaml:",omitempty"`                     // deprecation message of the option
+	ShorthandDeprecated string `yaml:"shorthand_deprecated,omitempty"` // deprecation message of the shorthand
+	Hidden              bool   `yaml:",omitempty"`
 }
 
 type Example struct {
@@ -81,18 +122,46 @@ type Command struct {
 	Options          Options   `yaml:",omitempty"`
 	InheritedOptions Options   `yaml:"inherited_options,omitempty"`
 	Examples         []Example `yaml:",omitempty"`
-	SeeAlso          []string  `yaml:"see_also,omitempty"` // not used
+	SeeAlso          []string  `yaml:"see_also,omitempty"` // done -> refsection{See also}
 	Usage            string    `yaml:",omitempty"`         // not used
+	Aliases          []string  `yaml:",omitempty"`         // done -> refnamediv
+	Deprecated       string    `yaml:",omitempty"`         // done -> warning
+	SuggestFor       []string  `yaml:"suggest_for,omitempty"`
+	Hidden           bool      `yaml:",omitempty"`
 }
 
+// Manifest contains the metadata of the book, read from the manifest.yaml file of a version
 type Manifest struct {
-	Docs      []Doc  `json:"docs,omitempty"`
-	Title     string `json:"title,omitempty"`
-	Copyright string `json:"copyright,omitempty"`
+	Title     string `yaml:",omitempty"` // defaults to the name of the product, followed by Reference
+	Subtitle  string `yaml:",omitempty"` // defaults to the version of kubectl of the spec
+	Copyright string `yaml:",omitempty"` // year of the copyright, defaults to the current year
+	Holder    string `yaml:",omitempty"`
+	Authors   string `yaml:",omitempty"`
+	Editor    string `yaml:",omitempty"`
+}
+
+// GetCommandLine returns the name of the command, prefixed by the binary and its parents
+// (e.g. "kubectl create deployment")
+func (o *Command) GetCommandLine(binary string) string {
+	return binary + " " + o.GetRefName()
+}
+
+// GetRefName returns the name of the command, prefixed by its parents (e.g. "create deployment")
+func (o *Command) GetRefName() string {
+	if len(o.Path) > 0 {
+		return strings.Replace(o.Path, "/", " ", 1) + " " + o.Name
+	}
+	return o.Name
 }
 
-type Doc struct {
-	Filename string `json:"filename,omitempty"`
+// GetAliasRefNames returns the names of the aliases of the command, prefixed by its parents
+// (e.g. "create deploy")
+func (o *Command) GetAliasRefNames() (names []string) {
+	prefix := strings.TrimSuffix(o.GetRefName(), o.Name)
+	for _, alias := range o.Aliases {
+		names = append(names, prefix+alias)
+	}
+	return
 }
 
 func (o *Command) GetAllOptionNames() (options []string) {
@@ -126,3 +195,79 @@ func (o *Command) GetInheritedOption(name string) *Option {
 	}
 	return nil
 }
+
+// FindOption returns the option of the command with the given name,
+// looking first at the options of the command, then at the inherited ones
+func (o *Command) FindOption(name string) *Option {
+	if option := o.GetOption(name); option != nil {
+		return option
+	}
+	return o.GetInheritedOption(name)
+}
+
+// GetSeeAlso returns the names of the commands related to the command, completed with the ones
+// given in the ToC, keeping only the commands documented in the ToC
+func (o *Command) GetSeeAlso(config *ToCCommand, toc *ToC) (seeAlso []string) {
+	seen := map[string]bool{config.Name: true}
+	for _, name := range append(append([]string{}, o.SeeAlso...), config.SeeAlso...) {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		if _, command := toc.GetCommand(name); command != nil {
+			seeAlso = append(seeAlso, name)
+		}
+	}
+	return
+}
+
+// visible returns the options documented: the hidden options are excepted, unless showHidden is true
+func (o Options) visible(showHidden bool) Options {
+	if showHidden {
+		return o
+	}
+	result := Options{}
+	for _, option := range o {
+		if !option.IsHidden() {
+			result = append(result, option)
+		}
+	}
+	return result
+}
+
+// IsHidden returns true if the option is hidden from the help. The deprecated options
+// are hidden by pflag, but are still documented to be marked as deprecated
+func (o *Option) IsHidden() bool {
+	return o.Hidden && len(o.Deprecated) == 0
+}
+
+// GetDeprecationNote returns a sentence describing the deprecation of the option or of its shorthand,
+// or an empty string if they are not deprecated
+func (o *Option) GetDeprecationNote(opts *GenerateOptions) string {
+	var notes []string
+	if len(o.Deprecated) > 0 {
+		notes = append(notes, fmt.Sprintf(opts.T("Deprecated: %s"), o.Deprecated))
+	}
+	if len(o.ShorthandDeprecated) > 0 && len(o.Shorthand) > 0 {
+		notes = append(notes, fmt.Sprintf(opts.T("-%s is deprecated: %s"), o.Shorthand, o.ShorthandDeprecated))
+	}
+	return strings.Join(notes, ". ")
+}
+
+// WithToC returns a copy of the option, with the values overridden in the ToC
+func (op *Option) WithToC(config *ToCOption) Option {
+	o := *op
+	if config.Type != nil {
+		o.Type = *config.Type
+	}
+	if config.Usage != nil {
+		o.Usage = *config.Usage
+	}
+	if config.Shorthand != nil {
+		o.Shorthand = *config.Shorthand
+	}
+	if config.Default != nil {
+		o.DefaultValue = *config.Default
+	}
+	return o
+}
@@ -0,0 +1,39 @@
+/*
+Copyright 2019 Philippe Martin.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"reflect"
+	"testing"
+)
+
+func optionNames(options Options) (names []string) {
+	for _, option := range options {
+		names = append(names, option.Name)
+	}
+	return
+}
+
+func TestGetGlobalOptionsHidden(t *testing.T) {
+	spec := hiddenTestSpec()
+	if got := optionNames(spec.GetGlobalOptions(false)); !reflect.DeepEqual(got, []string{"namespace"}) {
+		t.Errorf("got %v, want [namespace]", got)
+	}
+	if got := optionNames(spec.GetGlobalOptions(true)); !reflect.DeepEqual(got, []string{"namespace", "profile"}) {
+		t.Errorf("got %v, want [namespace profile]", got)
+	}
+}
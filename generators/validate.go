@@ -0,0 +1,247 @@
+/*
+Copyright 2019 Philippe Martin.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ProblemKind is the kind of a problem found when validating a ToC
+type ProblemKind string
+
+const (
+	UnknownCommand      ProblemKind = "unknown-command"
+	MissingCommand      ProblemKind = "missing-command"
+	DuplicatedCommand   ProblemKind = "duplicated-command"
+	UnknownOption       ProblemKind = "unknown-option"
+	MissingOption       ProblemKind = "missing-option"
+	DuplicatedOption    ProblemKind = "duplicated-option"
+	UnknownArg          ProblemKind = "unknown-arg"
+	DuplicatedID        ProblemKind = "duplicated-id"
+	OptionDeprecated    ProblemKind = "deprecated-option"
+	HiddenCommand       ProblemKind = "hidden-command"
+	HiddenOption        ProblemKind = "hidden-option"
+	UnknownGlobalOption ProblemKind = "unknown-global-option"
+)
+
+// Problem is a difference between the ToC and the kubectl command tree
+type Problem struct {
+	Kind     ProblemKind `json:"kind"`
+	Category string      `json:"category,omitempty"`
+	Command  string      `json:"command,omitempty"`
+	Option   string      `json:"option,omitempty"`
+	Arg      string      `json:"arg,omitempty"`
+	Message  string      `json:"message"`
+	// Warning is true for the entries of the ToC to review, which match the command tree (deprecated or hidden)
+	Warning bool `json:"warning,omitempty"`
+}
+
+// ValidateVersion validates the ToC of the Kubernetes version given in opts
+// against the kubectl command tree. The ToC is validated as written in the toc.yaml file
+func ValidateVersion(opts *GenerateOptions) ([]Problem, error) {
+	if len(opts.KubernetesVersion) < 1 {
+		return nil, fmt.Errorf("must specify --kubernetes-version")
+	}
+	toc, err := ReadToC(opts.GetTocFile())
+	if err != nil {
+		return nil, err
+	}
+	spec, err := LoadSpec(opts)
+	if err != nil {
+		return nil, err
+	}
+	return Validate(toc, spec), nil
+}
+
+// Validate returns all the problems found in the ToC, compared to the spec
+func Validate(toc *ToC, spec *KubectlSpec) (problems []Problem) {
+	commandsInToC := map[string]struct{}{}
+	categoryIDs := map[string]string{}
+
+	for _, name := range toc.InlineGlobalOptions {
+		if spec.GetGlobalOption(name) == nil {
+			problems = append(problems, Problem{
+				Kind:    UnknownGlobalOption,
+				Option:  name,
+				Message: fmt.Sprintf("global option %s not found", name),
+			})
+		}
+	}
+
+	for _, category := range toc.Categories {
+		if previous, found := categoryIDs[category.GetID()]; found {
+			problems = append(problems, Problem{
+				Kind:     DuplicatedID,
+				Category: category.Name,
+				Message:  fmt.Sprintf("categories %q and %q have the same id %s, set the id of one of them", previous, category.Name, category.GetID()),
+			})
+		}
+		categoryIDs[category.GetID()] = category.Name
+
+		for _, tocCommand := range category.Commands {
+			if _, found := commandsInToC[tocCommand.Name]; found {
+				problems = append(problems, Problem{
+					Kind:    DuplicatedCommand,
+					Command: tocCommand.Name,
+					Message: fmt.Sprintf("command %s is defined several times", tocCommand.Name),
+				})
+				continue
+			}
+			commandsInToC[tocCommand.Name] = struct{}{}
+
+			command := spec.GetCommand(tocCommand.Name)
+			if command == nil {
+				problems = append(problems, Problem{
+					Kind:    UnknownCommand,
+					Command: tocCommand.Name,
+					Message: fmt.Sprintf("command %s not found", tocCommand.Name),
+				})
+				continue
+			}
+			if command.Hidden {
+				problems = append(problems, Problem{
+					Kind:    HiddenCommand,
+					Command: tocCommand.Name,
+					Message: fmt.Sprintf("command %s is hidden", tocCommand.Name),
+					Warning: true,
+				})
+			}
+			problems = append(problems, tocCommand.Validate(command)...)
+		}
+	}
+
+	for _, name := range spec.GetAllCommandNames() {
+		if spec.GetCommand(name).Hidden {
+			continue
+		}
+		if _, found := commandsInToC[name]; !found {
+			problems = append(problems, Problem{
+				Kind:    MissingCommand,
+				Command: name,
+				Message: fmt.Sprintf("command %s not found in ToC", name),
+			})
+		}
+	}
+	return problems
+}
+
+// Validate returns the problems found in the ToC command, compared to the spec command
+func (o *ToCCommand) Validate(command *Command) (problems []Problem) {
+	optionsInToC := map[string]string{}
+
+	for _, group := range o.OptionsGroups {
+		for _, tocOption := range group.Options {
+			if previous, found := optionsInToC[tocOption.Name]; found {
+				problems = append(problems, Problem{
+					Kind:    DuplicatedOption,
+					Command: o.Name,
+					Option:  tocOption.Name,
+					Message: fmt.Sprintf("option %s of command %s is defined in groups %q and %q", tocOption.Name, o.Name, previous, group.Name),
+				})
+				continue
+			}
+			optionsInToC[tocOption.Name] = group.Name
+
+			option := command.FindOption(tocOption.Name)
+			if option == nil {
+				problems = append(problems, Problem{
+					Kind:    UnknownOption,
+					Command: o.Name,
+					Option:  tocOption.Name,
+					Message: fmt.Sprintf("option %s of command %s not found", tocOption.Name, o.Name),
+				})
+				continue
+			}
+			if len(option.Deprecated) > 0 {
+				problems = append(problems, Problem{
+					Kind:    OptionDeprecated,
+					Command: o.Name,
+					Option:  tocOption.Name,
+					Message: fmt.Sprintf("option %s of command %s is deprecated: %s", tocOption.Name, o.Name, option.Deprecated),
+					Warning: true,
+				})
+			}
+			if option.IsHidden() {
+				problems = append(problems, Problem{
+					Kind:    HiddenOption,
+					Command: o.Name,
+					Option:  tocOption.Name,
+					Message: fmt.Sprintf("option %s of command %s is hidden", tocOption.Name, o.Name),
+					Warning: true,
+				})
+			}
+		}
+	}
+
+	for _, name := range o.InlineGlobalOptions {
+		if command.GetInheritedOption(name) == nil {
+			problems = append(problems, Problem{
+				Kind:    UnknownGlobalOption,
+				Command: o.Name,
+				Option:  name,
+				Message: fmt.Sprintf("global option %s of command %s not found", name, o.Name),
+			})
+		}
+	}
+
+	for _, name := range command.GetAllOptionNames() {
+		if command.GetOption(name).Hidden {
+			continue
+		}
+		if _, found := optionsInToC[name]; !found {
+			problems = append(problems, Problem{
+				Kind:    MissingOption,
+				Command: o.Name,
+				Option:  name,
+				Message: fmt.Sprintf("option %s of command %s not found in ToC", name, o.Name),
+			})
+		}
+	}
+
+	usage := command.Usage
+	if len(usage) == 0 {
+		usage = o.Usage
+	}
+	for _, arg := range o.Args {
+		if !usageContainsArg(usage, arg.Name) {
+			problems = append(problems, Problem{
+				Kind:    UnknownArg,
+				Command: o.Name,
+				Arg:     arg.Name,
+				Message: fmt.Sprintf("arg %s of command %s not found in usage %q", arg.Name, o.Name, usage),
+			})
+		}
+	}
+	return problems
+}
+
+// usageContainsArg returns true if all the words of the arg are part of the usage,
+// ignoring case and punctuation (e.g. the arg fileSpecSrc is found in "cp <file-spec-src>")
+func usageContainsArg(usage string, arg string) bool {
+	isNotAlnum := func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}
+	normalizedUsage := strings.Join(strings.FieldsFunc(strings.ToLower(usage), isNotAlnum), "")
+	for _, word := range strings.FieldsFunc(strings.ToLower(arg), isNotAlnum) {
+		if !strings.Contains(normalizedUsage, word) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2019 Philippe Martin.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestToCCommandValidate(t *testing.T) {
+	command := &Command{
+		Name:  "run",
+		Usage: "run NAME --image=image [--env=\"key=value\"] [COMMAND] [args...]",
+		Options: Options{
+			{Name: "image", Type: "string"},
+			{Name: "env", Type: "stringArray"},
+			{Name: "record", Type: "bool", Hidden: true, Deprecated: "will be removed"},
+			{Name: "generator", Type: "string", Hidden: true},
+		},
+		InheritedOptions: Options{
+			{Name: "namespace", Type: "string"},
+		},
+	}
+	group := func(name string, options ...string) OptionsGroup {
+		group := OptionsGroup{Name: name}
+		for _, option := range options {
+			group.Options = append(group.Options, ToCOption{Name: option})
+		}
+		return group
+	}
+	tests := []struct {
+		name       string
+		tocCommand *ToCCommand
+		want       []ProblemKind
+	}{
+		{
+			name: "valid",
+			tocCommand: &ToCCommand{
+				Name:          "run",
+				OptionsGroups: []OptionsGroup{group("Options", "image", "env")},
+				Args:          []Arg{{Name: "name"}, {Name: "command"}},
+			},
+		},
+		{
+			name: "inherited option in a group",
+			tocCommand: &ToCCommand{
+				Name:          "run",
+				OptionsGroups: []OptionsGroup{group("Options", "image", "env", "namespace")},
+			},
+		},
+		{
+			name: "unknown option",
+			tocCommand: &ToCCommand{
+				Name:          "run",
+				OptionsGroups: []OptionsGroup{group("Options", "image", "env", "port")},
+			},
+			want: []ProblemKind{UnknownOption},
+		},
+		{
+			name: "missing option",
+			tocCommand: &ToCCommand{
+				Name:          "run",
+				OptionsGroups: []OptionsGroup{group("Options", "image")},
+			},
+			want: []ProblemKind{MissingOption},
+		},
+		{
+			name: "duplicated option",
+			tocCommand: &ToCCommand{
+				Name:          "run",
+				OptionsGroups: []OptionsGroup{group("Options", "image", "env"), group("Other options", "image")},
+			},
+			want: []ProblemKind{DuplicatedOption},
+		},
+		{
+			name: "deprecated option",
+			tocCommand: &ToCCommand{
+				Name:          "run",
+				OptionsGroups: []OptionsGroup{group("Options", "image", "env", "record")},
+			},
+			want: []ProblemKind{OptionDeprecated},
+		},
+		{
+			name: "hidden option",
+			tocCommand: &ToCCommand{
+				Name:          "run",
+				OptionsGroups: []OptionsGroup{group("Options", "image", "env", "generator")},
+			},
+			want: []ProblemKind{HiddenOption},
+		},
+		{
+			name: "inline global options",
+			tocCommand: &ToCCommand{
+				Name:                "run",
+				OptionsGroups:       []OptionsGroup{group("Options", "image", "env")},
+				InlineGlobalOptions: []string{"namespace", "nmespace"},
+			},
+			want: []ProblemKind{UnknownGlobalOption},
+		},
+		{
+			name: "unknown arg",
+			tocCommand: &ToCCommand{
+				Name:          "run",
+				OptionsGroups: []OptionsGroup{group("Options", "image", "env")},
+				Args:          []Arg{{Name: "filename"}},
+			},
+			want: []ProblemKind{UnknownArg},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []ProblemKind
+			for _, problem := range tt.tocCommand.Validate(command) {
+				got = append(got, problem.Kind)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateInlineGlobalOptions(t *testing.T) {
+	toc := &ToC{
+		InlineGlobalOptions: []string{"namespace", "profile", "nmespace"},
+		Categories: []*Category{{
+			Name: "Basic Commands",
+			Commands: []*ToCCommand{{
+				Name:          "get",
+				OptionsGroups: []OptionsGroup{{Name: "Options", Options: []ToCOption{{Name: "output"}}}},
+			}},
+		}},
+	}
+	var got []Problem
+	for _, problem := range Validate(toc, hiddenTestSpec()) {
+		got = append(got, Problem{Kind: problem.Kind, Command: problem.Command, Option: problem.Option})
+	}
+	want := []Problem{{Kind: UnknownGlobalOption, Option: "nmespace"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
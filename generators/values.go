@@ -0,0 +1,74 @@
+/*
+Copyright 2019 Philippe Martin.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"regexp"
+	"strings"
+)
+
+// enumerationRegexp matches the enumerations of values in the usage of an option
+// (e.g. Must be "none", "server", or "client". One of: (json, yaml, name))
+var enumerationRegexp = regexp.MustCompile(`(?i)\b(?:must be(?: one of)?|one of)\s*:?\s*(\([^)]*\)|[^.]*)`)
+
+var enumerationSeparatorRegexp = regexp.MustCompile(`\s*,\s*(?:or\s+|and\s+)?|\s+or\s+`)
+
+var enumerationCommentRegexp = regexp.MustCompile(`\s*\([^)]*\)`)
+
+var enumerationValueRegexp = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// GetValues returns the values accepted by the option, as given in the ToC,
+// or detected in the usage of the option. Only the values of string options are detected
+func (op *Option) GetValues(config *ToCOption) []string {
+	if len(config.Values) > 0 {
+		return config.Values
+	}
+	o := op.WithToC(config)
+	if o.Type != "string" {
+		return nil
+	}
+	return detectValues(o.Usage)
+}
+
+// detectValues returns the values enumerated in the usage of an option, or nil if
+// the usage does not contain an enumeration of at least two values
+func detectValues(usage string) []string {
+	matches := enumerationRegexp.FindStringSubmatch(usage)
+	if matches == nil {
+		return nil
+	}
+	enumeration := strings.TrimSpace(matches[1])
+	if strings.HasPrefix(enumeration, "(") {
+		enumeration = strings.TrimSuffix(strings.TrimPrefix(enumeration, "("), ")")
+	} else {
+		// the comments of the values, e.g. strict (or true)
+		enumeration = enumerationCommentRegexp.ReplaceAllString(enumeration, "")
+	}
+
+	var values []string
+	for _, value := range enumerationSeparatorRegexp.Split(strings.TrimSpace(enumeration), -1) {
+		value = strings.Trim(value, "\"'` ")
+		if !enumerationValueRegexp.MatchString(value) {
+			return nil
+		}
+		values = append(values, value)
+	}
+	if len(values) < 2 {
+		return nil
+	}
+	return values
+}
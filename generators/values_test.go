@@ -0,0 +1,113 @@
+/*
+Copyright 2019 Philippe Martin.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDetectValues(t *testing.T) {
+	tests := []struct {
+		name  string
+		usage string
+		want  []string
+	}{
+		{
+			name:  "must be",
+			usage: `Must be "none", "server", or "client". If client strategy, only print the object that would be sent.`,
+			want:  []string{"none", "server", "client"},
+		},
+		{
+			name:  "one of, in parentheses",
+			usage: `Output format. One of: (json, yaml, name, go-template, go-template-file, template, templatefile, jsonpath, jsonpath-as-json, jsonpath-file).`,
+			want:  []string{"json", "yaml", "name", "go-template", "go-template-file", "template", "templatefile", "jsonpath", "jsonpath-as-json", "jsonpath-file"},
+		},
+		{
+			name:  "must be one of, with comments",
+			usage: `Must be one of: strict (or true), warn, ignore (or false). "true" or "strict" will use a schema to validate the input.`,
+			want:  []string{"strict", "warn", "ignore"},
+		},
+		{
+			name:  "one of, separated by or",
+			usage: `The image pull policy, one of Always or Never.`,
+			want:  []string{"Always", "Never"},
+		},
+		{
+			name:  "no enumeration",
+			usage: `Name of the manager used to track field ownership.`,
+			want:  nil,
+		},
+		{
+			name:  "single value",
+			usage: `Must be "background".`,
+			want:  nil,
+		},
+		{
+			name:  "sentence, not values",
+			usage: `One of the containers of the pod, the first one by default.`,
+			want:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectValues(tt.usage); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetValues(t *testing.T) {
+	stringType := "string"
+	tests := []struct {
+		name   string
+		option Option
+		config ToCOption
+		want   []string
+	}{
+		{
+			name:   "detected for a string option",
+			option: Option{Name: "dry-run", Type: "string", Usage: `Must be "none", "server", or "client".`},
+			want:   []string{"none", "server", "client"},
+		},
+		{
+			name:   "not detected for other types",
+			option: Option{Name: "all", Type: "bool", Usage: `Must be "true" or "false".`},
+			want:   nil,
+		},
+		{
+			name:   "given in the ToC",
+			option: Option{Name: "image-pull-policy", Type: "string", Usage: `The image pull policy for the container.`},
+			config: ToCOption{Values: []string{"Always", "IfNotPresent", "Never"}},
+			want:   []string{"Always", "IfNotPresent", "Never"},
+		},
+		{
+			name:   "type overridden in the ToC",
+			option: Option{Name: "mode", Type: "unknown", Usage: `One of: (fast, slow).`},
+			config: ToCOption{Type: &stringType},
+			want:   []string{"fast", "slow"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.option.GetValues(&tt.config); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -5,7 +5,6 @@ go 1.22.0
 toolchain go1.22.9
 
 require (
-	github.com/jinzhu/copier v0.4.0
 	github.com/spf13/cobra v1.8.1
 	github.com/spf13/pflag v1.0.5
 	gopkg.in/yaml.v2 v2.4.0
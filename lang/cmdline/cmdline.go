@@ -0,0 +1,40 @@
+/*
+Copyright 2019 Philippe Martin.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cmdline loads the kubectl translations of the language given with the --lang flag
+// of the command line, when it is initialized. As the help texts of kubectl are translated
+// when the kubectl packages are initialized, it must be imported by the main package only:
+//
+//	import (
+//		_ "github.com/feloy/kubectl-reference/lang/cmdline"
+//	)
+//
+// It is initialized before the kubectl packages, as the packages are initialized in the order
+// of their import paths once their dependencies are initialized.
+package cmdline
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/feloy/kubectl-reference/lang"
+)
+
+func init() {
+	if err := lang.LoadFromArgs(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load the translations: %v\n", err)
+	}
+}
@@ -0,0 +1,220 @@
+/*
+Copyright 2019 Philippe Martin.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package lang loads the kubectl translations of a language, and translates the texts of the books.
+//
+// The help texts of kubectl are translated when the kubectl packages are initialized, so the
+// translations must be loaded before, from the init function of a package: the cmdline package
+// loads them for the language given with the --lang flag.
+package lang
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/kubectl/pkg/util/i18n"
+)
+
+// languages maps the names accepted by --lang to the languages of the kubectl translations
+var languages = map[string]string{
+	"fr":    "fr_FR",
+	"fr_FR": "fr_FR",
+	"ja":    "ja_JP",
+	"ja_JP": "ja_JP",
+}
+
+// loaded is the language of the kubectl translations loaded
+var loaded string
+
+// LoadFromArgs makes kubectl load the translations of the language given with the --lang flag in args,
+// if any. It must be called before the kubectl packages are initialized, see the cmdline package.
+// An unknown language is ignored, and reported by Check
+func LoadFromArgs(args []string) error {
+	language := Get(fromArgs(args))
+	if len(language) == 0 {
+		return nil
+	}
+	return i18n.SetLoadTranslationsFunc(func() error {
+		if err := i18n.LoadTranslations("kubectl", func() string { return language }); err != nil {
+			return err
+		}
+		loaded = language
+		return nil
+	})
+}
+
+// fromArgs returns the value of the --lang flag in args, if any
+func fromArgs(args []string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if value, found := strings.CutPrefix(arg, "--lang="); found {
+			return value
+		}
+		if arg == "--lang" && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
+// Get returns the language of the kubectl translations for the given name (e.g. fr_FR for fr),
+// or an empty string if the language is not supported
+func Get(name string) string {
+	return languages[strings.ReplaceAll(name, "-", "_")]
+}
+
+// Tag returns the language tag of the language for the given name (e.g. fr-FR for fr),
+// or an empty string if the language is not supported
+func Tag(name string) string {
+	return strings.ReplaceAll(Get(name), "_", "-")
+}
+
+// Names returns the names accepted for the languages
+func Names() []string {
+	return []string{"fr", "ja"}
+}
+
+// Check returns an error if the language is not supported or, if translations is true,
+// if its kubectl translations have not been loaded
+func Check(name string, translations bool) error {
+	language := Get(name)
+	if len(language) == 0 {
+		return fmt.Errorf("unknown language %q, must be one of %v", name, Names())
+	}
+	if translations && loaded != language {
+		return fmt.Errorf("the kubectl translations for %s are not loaded, the language must be given with the --lang flag", name)
+	}
+	return nil
+}
+
+// T returns the text translated in the given language, or the text itself
+// if the language is empty or the text is not translated
+func T(name string, text string) string {
+	if translated, found := texts[Get(name)][text]; found {
+		return translated
+	}
+	return text
+}
+
+// texts are the translations of the texts of the books, by language
+var texts = map[string]map[string]string{
+	"fr_FR": {
+		"%s Reference":   "Référence de %s",
+		"Usage":          "Utilisation",
+		"Original Usage": "Utilisation d'origine",
+		"Description":    "Description",
+		"Options":        "Options",
+		"Examples":       "Exemples",
+		"See also":       "Voir aussi",
+		"Global options": "Options globales",
+		"The following options can be passed to any command.": "Les options suivantes peuvent être passées à toutes les commandes.",
+		"The global options are also accepted, see %s.":       "Les options globales sont également acceptées, voir %s.",
+		"This command is deprecated: %s":                      "Cette commande est obsolète : %s",
+		"defaults to %s":                                      "%s par défaut",
+		"deprecated":                                          "obsolète",
+		"Value":                                               "Valeur",
+		"(default)":                                           "(par défaut)",
+		"Options by command":                                  "Options par commande",
+		"Name":                                                "Nom",
+		"Synopsis":                                            "Synopsis",
+		"Type":                                                "Type",
+		"Default":                                             "Défaut",
+		"Deprecated: %s":                                      "Obsolète : %s",
+		"-%s is deprecated: %s":                               "-%s est obsolète : %s",
+		"Option":                                              "Option",
+		"Command":                                             "Commande",
+		"Type (default)":                                      "Type (par défaut)",
+		"By %s":                                               "Par %s",
+		"Edited and published by %s":                          "Édité et publié par %s",
+		"Permission is granted to copy, distribute and/or modify this document under the terms of the Apache License version 2. A copy of the license is included in %s.": "Vous êtes autorisé à copier, distribuer et/ou modifier ce document selon les termes de la licence Apache version 2. Une copie de la licence est incluse dans %s.",
+		"The tool used to generate this document is available at %s": "L'outil utilisé pour générer ce document est disponible sur %s",
+		"Changes from %s to %s":             "Changements de %s à %s",
+		"No changes.":                       "Aucun changement.",
+		"New commands":                      "Nouvelles commandes",
+		"Removed commands":                  "Commandes supprimées",
+		"Changed command usages":            "Utilisations de commandes modifiées",
+		"New options":                       "Nouvelles options",
+		"Removed options":                   "Options supprimées",
+		"Deprecated options":                "Options obsolètes",
+		"Changed default values":            "Valeurs par défaut modifiées",
+		"Changed types":                     "Types modifiés",
+		"Changed option usages":             "Utilisations d'options modifiées",
+		"new command %s":                    "nouvelle commande %s",
+		"removed command %s":                "commande %s supprimée",
+		"usage of %s changed from %s to %s": "l'utilisation de %s est passée de %s à %s",
+		"new option %s in %s":               "nouvelle option %s dans %s",
+		"removed option %s from %s":         "option %s supprimée de %s",
+		"option %s of %s is deprecated: %s": "l'option %s de %s est obsolète : %s",
+		"default value of option %s in %s changed from %s to %s": "la valeur par défaut de l'option %s de %s est passée de %s à %s",
+		"type of option %s in %s changed from %s to %s":          "le type de l'option %s de %s est passé de %s à %s",
+		"usage of option %s in %s changed to: %s":                "l'utilisation de l'option %s de %s est devenue : %s",
+		"global option": "option globale",
+	},
+	"ja_JP": {
+		"%s Reference":   "%s リファレンス",
+		"Usage":          "使い方",
+		"Original Usage": "元の使い方",
+		"Description":    "説明",
+		"Options":        "オプション",
+		"Examples":       "例",
+		"See also":       "関連項目",
+		"Global options": "グローバルオプション",
+		"The following options can be passed to any command.": "以下のオプションはすべてのコマンドで使用できます。",
+		"The global options are also accepted, see %s.":       "グローバルオプションも使用できます。%sを参照してください。",
+		"This command is deprecated: %s":                      "このコマンドは非推奨です: %s",
+		"defaults to %s":                                      "デフォルト: %s",
+		"deprecated":                                          "非推奨",
+		"Value":                                               "値",
+		"(default)":                                           "(デフォルト)",
+		"Options by command":                                  "コマンド別オプション",
+		"Name":                                                "名前",
+		"Synopsis":                                            "概要",
+		"Type":                                                "型",
+		"Default":                                             "デフォルト",
+		"Deprecated: %s":                                      "非推奨: %s",
+		"-%s is deprecated: %s":                               "-%s は非推奨です: %s",
+		"Option":                                              "オプション",
+		"Command":                                             "コマンド",
+		"Type (default)":                                      "型 (デフォルト)",
+		"By %s":                                               "著者: %s",
+		"Edited and published by %s":                          "編集・発行: %s",
+		"Permission is granted to copy, distribute and/or modify this document under the terms of the Apache License version 2. A copy of the license is included in %s.": "この文書は Apache License バージョン 2 の条件に従って複製、配布、改変することができます。ライセンスの写しは%sに含まれています。",
+		"The tool used to generate this document is available at %s": "この文書の生成に使用したツールは %s で入手できます",
+		"Changes from %s to %s":             "%s から %s への変更",
+		"No changes.":                       "変更はありません。",
+		"New commands":                      "新しいコマンド",
+		"Removed commands":                  "削除されたコマンド",
+		"Changed command usages":            "使い方が変更されたコマンド",
+		"New options":                       "新しいオプション",
+		"Removed options":                   "削除されたオプション",
+		"Deprecated options":                "非推奨になったオプション",
+		"Changed default values":            "変更されたデフォルト値",
+		"Changed types":                     "変更された型",
+		"Changed option usages":             "使い方が変更されたオプション",
+		"new command %s":                    "新しいコマンド %s",
+		"removed command %s":                "コマンド %s が削除されました",
+		"usage of %s changed from %s to %s": "%s の使い方が %s から %s に変更されました",
+		"new option %s in %s":               "%[2]s の新しいオプション %[1]s",
+		"removed option %s from %s":         "%[2]s からオプション %[1]s が削除されました",
+		"option %s of %s is deprecated: %s": "%[2]s のオプション %[1]s は非推奨です: %[3]s",
+		"default value of option %s in %s changed from %s to %s": "%[2]s のオプション %[1]s のデフォルト値が %[3]s から %[4]s に変更されました",
+		"type of option %s in %s changed from %s to %s":          "%[2]s のオプション %[1]s の型が %[3]s から %[4]s に変更されました",
+		"usage of option %s in %s changed to: %s":                "%[2]s のオプション %[1]s の使い方が変更されました: %[3]s",
+		"global option": "グローバルオプション",
+	},
+}
@@ -17,12 +17,15 @@ limitations under the License.
 package main
 
 import (
-	"flag"
+	"os"
 
-	"github.com/feloy/kubectl-reference/generators"
+	"github.com/feloy/kubectl-reference/cmd"
+	// loads the kubectl translations of the --lang flag, before the kubectl packages are initialized
+	_ "github.com/feloy/kubectl-reference/lang/cmdline"
 )
 
 func main() {
-	flag.Parse()
-	generators.AsDocbook()
+	if err := cmd.NewRootCommand().Execute(); err != nil {
+		os.Exit(1)
+	}
 }
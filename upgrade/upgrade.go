@@ -16,46 +16,36 @@ limitations under the License.
 package upgrade
 
 import (
-	"flag"
-	"fmt"
-	"io/ioutil"
-	"os"
-	"path/filepath"
+	"io"
 
 	"github.com/feloy/kubectl-reference/generators"
 	"gopkg.in/yaml.v2"
 )
 
-func getTocFile() string {
-	return filepath.Join(*generators.GenKubectlDir, *generators.KubernetesVersion, "toc.yaml")
-}
-
-func Upgrade() {
-	flag.Parse()
-
-	toc := generators.ToC{}
-	if len(getTocFile()) < 1 {
-		fmt.Printf("Must specify --toc-file.\n")
-		os.Exit(2)
-	}
-
-	contents, err := ioutil.ReadFile(getTocFile())
+// Upgrade writes to w the ToC of the given version completed with the commands,
+// options and usages found in the kubectl command tree.
+// The commands of the ToC not found in the kubectl command tree are returned as errors
+func Upgrade(w io.Writer, opts *generators.GenerateOptions) error {
+	toc, err := generators.ReadToC(opts.GetTocFile())
 	if err != nil {
-		fmt.Printf("Failed to read yaml file %s: %v", getTocFile(), err)
+		return err
 	}
 
-	err = yaml.Unmarshal(contents, &toc)
+	spec, err := generators.LoadSpec(opts)
 	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		return err
 	}
 
-	spec := generators.GetSpec()
+	toc.AddMissingCommands(spec)
+	errOptions := toc.AddMissingOptions(spec)
+	toc.AddMissingUsages(spec)
 
-	toc.AddMissingCommands(&spec)
-	toc.AddMissingOptions(&spec)
-	toc.AddMissingUsages(&spec)
-
-	bytes, _ := yaml.Marshal(toc)
-	fmt.Print(string(bytes))
+	bytes, err := yaml.Marshal(toc)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(bytes); err != nil {
+		return err
+	}
+	return errOptions
 }